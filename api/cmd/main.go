@@ -5,11 +5,15 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"api/internal/core/db"
+	"api/internal/core/media"
 	"api/internal/core/message"
 	"api/internal/core/notifications"
+	"api/internal/core/producer"
 	"api/internal/core/score"
+	"api/internal/core/storage"
 	"api/internal/middleware"
 	"api/internal/routes"
 
@@ -55,14 +59,40 @@ func main() {
 		log.Fatalf("Error intializing Firebase Messing Client: %v", err)
 	}
 
+	store, err := storage.FromEnv(ctx)
+	if err != nil {
+		log.Fatalf("Error initializing object storage: %v", err)
+	}
+
 	var logFile *os.File
 	defer logFile.Close()
 
 	middleware.SetupMiddleware(router, logFile)
 
-	hub := message.NewHub()
+	hub := message.NewHub(queries)
+	hub.Start(context.Background())
+
+	// Every replica listens on Postgres as well as Kafka, so events still fan
+	// out across replicas when a producer has to fall back to NOTIFY.
+	message.SetupPostgresListener(conn, hub)
+	var broker producer.Broker
+	kafkaBroker, brokerErr := producer.NewKafkaBroker(message.KafkaBrokers())
+	if brokerErr != nil {
+		log.Printf("Kafka producer unavailable, publishing events through Postgres: %v", brokerErr)
+		broker = producer.NewPostgresBroker(queries)
+	} else {
+		broker = kafkaBroker
+	}
+	eventStore := producer.NewPostgresStore(queries, groupEventRetention())
+	eventStore.Start(context.Background(), time.Hour)
+	publisher := producer.NewPublisher(broker, eventStore)
+	defer publisher.Close()
+
+	uploadJanitor := media.NewUploadJanitor(queries, store, time.Hour)
+	uploadJanitor.Start(context.Background(), 10*time.Minute)
 
-	go score.InitialScore(queries)
+	scheduler := score.NewScheduler(queries, time.Minute, 4)
+	scheduler.Start(context.Background())
 
 	routes.SetupCoreRouter(
 		router,
@@ -70,7 +100,25 @@ func main() {
 		authClient,
 		messagingClient,
 		hub,
+		store,
+		publisher,
+		scheduler,
 	)
 	router.SetTrustedProxies([]string{"192.168.100.0/24"})
 	router.Run()
 }
+
+// groupEventRetention is how long events are kept for replay, from
+// GROUP_EVENT_RETENTION and a day by default.
+func groupEventRetention() time.Duration {
+	value := os.Getenv("GROUP_EVENT_RETENTION")
+	if value == "" {
+		return 24 * time.Hour
+	}
+	retention, err := time.ParseDuration(value)
+	if err != nil || retention <= 0 {
+		log.Printf("Ignoring invalid GROUP_EVENT_RETENTION %q", value)
+		return 24 * time.Hour
+	}
+	return retention
+}
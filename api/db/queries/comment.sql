@@ -0,0 +1,65 @@
+-- name: CreateComment :one
+INSERT INTO comments (
+    id,
+    post_id,
+    group_id,
+    user_id,
+    parent_id,
+    content,
+    date_created
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: GetComment :one
+SELECT *
+FROM comments
+WHERE id = $1;
+
+-- name: UpdateCommentContent :one
+UPDATE comments
+SET content = $2,
+    date_updated = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteComment :exec
+DELETE FROM comments
+WHERE id = $1;
+
+-- name: InitialCommentsForPost :many
+SELECT sqlc.embed(comments),
+    (SELECT COUNT(*) FROM comments replies WHERE replies.parent_id = comments.id)::bigint AS reply_count
+FROM comments
+WHERE comments.group_id = $1
+  AND comments.post_id = $2
+  AND comments.parent_id IS NULL
+ORDER BY comments.date_created, comments.id
+LIMIT $3;
+
+-- name: ListPaginatedCommentsForPost :many
+SELECT sqlc.embed(comments),
+    (SELECT COUNT(*) FROM comments replies WHERE replies.parent_id = comments.id)::bigint AS reply_count
+FROM comments
+WHERE comments.group_id = $1
+  AND comments.post_id = $2
+  AND comments.parent_id IS NULL
+  AND (comments.date_created, comments.id) > ($3, $4::uuid)
+ORDER BY comments.date_created, comments.id
+LIMIT $5;
+
+-- name: InitialRepliesForComment :many
+SELECT *
+FROM comments
+WHERE parent_id = $1::uuid
+ORDER BY date_created, id
+LIMIT $2;
+
+-- name: ListPaginatedRepliesForComment :many
+SELECT *
+FROM comments
+WHERE parent_id = $1::uuid
+  AND (date_created, id) > ($2, $3::uuid)
+ORDER BY date_created, id
+LIMIT $4;
@@ -0,0 +1,23 @@
+-- name: TouchUserConnections :exec
+INSERT INTO user_connections (
+    user_id,
+    replica,
+    expires_at
+)
+SELECT unnest(sqlc.arg(user_ids)::uuid[]), sqlc.arg(replica)::text, sqlc.arg(expires_at)::timestamptz
+ON CONFLICT (user_id, replica) DO UPDATE
+SET expires_at = EXCLUDED.expires_at;
+
+-- name: DeleteUserConnection :exec
+DELETE FROM user_connections
+WHERE user_id = $1 AND replica = $2;
+
+-- name: DeleteExpiredUserConnections :exec
+DELETE FROM user_connections
+WHERE expires_at < now();
+
+-- name: CheckUserOnline :one
+SELECT EXISTS (
+    SELECT 1 FROM user_connections
+    WHERE user_id = $1 AND expires_at > now()
+);
@@ -0,0 +1,39 @@
+-- name: CreateDirectMessage :one
+INSERT INTO direct_messages (
+    id,
+    sender_id,
+    recipient_id,
+    content,
+    date_created
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetDirectMessage :one
+SELECT * FROM direct_messages
+WHERE id = $1;
+
+-- name: InitialDirectMessages :many
+-- Conversations are read newest first, paging back through history.
+SELECT * FROM direct_messages
+WHERE (sender_id = $1 AND recipient_id = $2)
+   OR (sender_id = $2 AND recipient_id = $1)
+ORDER BY date_created DESC, id DESC
+LIMIT $3;
+
+-- name: ListPaginatedDirectMessages :many
+SELECT * FROM direct_messages
+WHERE ((sender_id = $1 AND recipient_id = $2)
+    OR (sender_id = $2 AND recipient_id = $1))
+  AND (date_created, id) < ($3, $4::uuid)
+ORDER BY date_created DESC, id DESC
+LIMIT $5;
+
+-- name: MarkDirectMessagesRead :execrows
+UPDATE direct_messages
+SET date_read = $3
+WHERE recipient_id = $1
+  AND sender_id = $2
+  AND date_read IS NULL
+  AND date_created <= $4;
@@ -0,0 +1,56 @@
+-- name: NextGroupEventSequence :one
+INSERT INTO group_event_sequences (
+    group_id,
+    last_sequence
+) VALUES (
+    $1, 1
+)
+ON CONFLICT (group_id) DO UPDATE
+SET last_sequence = group_event_sequences.last_sequence + 1
+RETURNING last_sequence;
+
+-- name: NextUserEventSequence :one
+INSERT INTO user_event_sequences (
+    user_id,
+    last_sequence
+) VALUES (
+    $1, 1
+)
+ON CONFLICT (user_id) DO UPDATE
+SET last_sequence = user_event_sequences.last_sequence + 1
+RETURNING last_sequence;
+
+-- name: CreateGroupEvent :exec
+INSERT INTO group_events (
+    group_id,
+    sequence,
+    id,
+    payload,
+    date_created
+) VALUES (
+    $1, $2, $3, $4, $5
+);
+
+-- name: ListGroupEventsAfter :many
+SELECT * FROM group_events
+WHERE group_id = $1 AND sequence > $2
+ORDER BY sequence
+LIMIT $3;
+
+-- name: DeleteGroupEventsBefore :exec
+DELETE FROM group_events
+WHERE date_created < $1;
+
+-- name: GetGroupEventSequence :one
+SELECT last_sequence FROM group_event_sequences
+WHERE group_id = $1;
+
+-- name: GetGroupEvent :one
+SELECT * FROM group_events
+WHERE group_id = $1 AND sequence = $2;
+
+-- name: NotifyRealtimeEvent :exec
+SELECT pg_notify('realtime_events', sqlc.arg(payload)::text);
+
+-- name: ListenRealtimeEvents :exec
+LISTEN realtime_events;
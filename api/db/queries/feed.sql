@@ -0,0 +1,40 @@
+-- name: InitialFeedForGroups :many
+SELECT sqlc.embed(posts), feed.score, feed.groups
+FROM (
+    SELECT
+        fgp.post_id,
+        MAX(fgp.score)::decimal AS score,
+        ARRAY_AGG(fgp.group_id ORDER BY fgp.group_id)::uuid[] AS groups
+    FROM friend_group_posts fgp
+    WHERE fgp.group_id = ANY($1::uuid[])
+    GROUP BY fgp.post_id
+) feed
+JOIN posts ON posts.id = feed.post_id
+WHERE posts.status = 'PUBLISHED'
+  AND NOT EXISTS (
+      SELECT 1 FROM friendships f
+      WHERE f.user_id = posts.user_id AND f.friend_id = $3 AND f.status = 'BLOCKED'
+  )
+ORDER BY feed.score DESC, feed.post_id DESC
+LIMIT $2;
+
+-- name: ListPaginatedFeedForGroups :many
+SELECT sqlc.embed(posts), feed.score, feed.groups
+FROM (
+    SELECT
+        fgp.post_id,
+        MAX(fgp.score)::decimal AS score,
+        ARRAY_AGG(fgp.group_id ORDER BY fgp.group_id)::uuid[] AS groups
+    FROM friend_group_posts fgp
+    WHERE fgp.group_id = ANY($1::uuid[])
+    GROUP BY fgp.post_id
+) feed
+JOIN posts ON posts.id = feed.post_id
+WHERE posts.status = 'PUBLISHED'
+  AND (feed.score, feed.post_id) < ($2::decimal, $3::uuid)
+  AND NOT EXISTS (
+      SELECT 1 FROM friendships f
+      WHERE f.user_id = posts.user_id AND f.friend_id = $5 AND f.status = 'BLOCKED'
+  )
+ORDER BY feed.score DESC, feed.post_id DESC
+LIMIT $4;
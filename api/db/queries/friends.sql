@@ -39,3 +39,69 @@ WHERE user_id = $1;
 DELETE FROM friendships
 WHERE user_id = $1
   AND friend_id = $2;
+
+-- name: GetFriendshipBetween :one
+-- Friendships are stored once, in the direction the request was sent, so
+-- look the pair up both ways. A block wins over anything else.
+SELECT
+    user_id,
+    friend_id,
+    status,
+    date_created
+FROM friendships
+WHERE (user_id = $1 AND friend_id = $2)
+   OR (user_id = $2 AND friend_id = $1)
+ORDER BY status DESC
+LIMIT 1;
+
+-- name: DeleteFriendshipsBetween :exec
+DELETE FROM friendships
+WHERE (user_id = $1 AND friend_id = $2)
+   OR (user_id = $2 AND friend_id = $1);
+
+-- name: ListFriendshipsWithProfiles :many
+SELECT sqlc.embed(friendships), sqlc.embed(user_profiles)
+FROM friendships
+JOIN user_profiles ON user_profiles.user_id = CASE
+    WHEN friendships.user_id = $1 THEN friendships.friend_id
+    ELSE friendships.user_id
+END
+WHERE friendships.user_id = $1 OR friendships.friend_id = $1
+ORDER BY user_profiles.username;
+
+-- name: ListBlockedUserIDs :many
+-- Users the given user has blocked.
+SELECT friend_id FROM friendships
+WHERE user_id = $1 AND status = 'BLOCKED';
+
+-- name: ListBlockerIDs :many
+-- Users who have blocked the given user.
+SELECT user_id FROM friendships
+WHERE friend_id = $1 AND status = 'BLOCKED';
+
+-- name: CheckBlockedInGroup :one
+-- Whether the user has blocked, or been blocked by, anyone in the group.
+SELECT EXISTS (
+    SELECT 1
+    FROM friendships
+    JOIN friend_group_members
+      ON friend_group_members.user_id IN (friendships.user_id, friendships.friend_id)
+    WHERE friendships.status = 'BLOCKED'
+      AND (friendships.user_id = $1 OR friendships.friend_id = $1)
+      AND friend_group_members.group_id = $2
+      AND friend_group_members.user_id <> $1
+);
+
+-- name: BlockUser :one
+INSERT INTO friendships (
+    user_id,
+    friend_id,
+    status,
+    date_created
+) VALUES (
+    $1, $2, 'BLOCKED', $3
+)
+ON CONFLICT (user_id, friend_id) DO UPDATE
+SET status = 'BLOCKED',
+    date_created = EXCLUDED.date_created
+RETURNING user_id, friend_id, status, date_created;
@@ -42,6 +42,12 @@ SET name = $2
 WHERE id = $1
 RETURNING id, name, date_created, owner_id;
 
+-- name: UpdateFriendGroupOwner :one
+UPDATE friend_groups
+SET owner_id = $2
+WHERE id = $1
+RETURNING id, name, date_created, owner_id;
+
 -- name: DeleteFriendGroup :exec
 DELETE FROM friend_groups
 WHERE id = $1;
@@ -62,6 +68,23 @@ DELETE FROM friend_group_members
 WHERE group_id = $1
   AND user_id = $2;
 
+-- name: GetGroupMember :one
+SELECT
+    group_id,
+    user_id,
+    joined_at,
+    role
+FROM friend_group_members
+WHERE group_id = $1
+  AND user_id = $2;
+
+-- name: UpdateMemberRole :one
+UPDATE friend_group_members
+SET role = $3
+WHERE group_id = $1
+  AND user_id = $2
+RETURNING group_id, user_id, joined_at, role;
+
 -- name: ListGroupMembers :many
 SELECT
     group_id,
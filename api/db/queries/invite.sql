@@ -0,0 +1,39 @@
+-- name: CreateGroupInvite :one
+INSERT INTO group_invites (
+    code,
+    group_id,
+    created_by,
+    date_created,
+    expires_at,
+    max_uses
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: GetGroupInvite :one
+SELECT *
+FROM group_invites
+WHERE code = $1;
+
+-- name: ListGroupInvites :many
+SELECT *
+FROM group_invites
+WHERE group_id = $1
+  AND NOT revoked
+ORDER BY date_created DESC;
+
+-- name: RedeemGroupInvite :one
+-- Claims one use of the invite, failing with no rows if it can't be used.
+UPDATE group_invites
+SET uses = uses + 1
+WHERE code = $1
+  AND NOT revoked
+  AND (expires_at IS NULL OR expires_at > now())
+  AND (max_uses IS NULL OR uses < max_uses)
+RETURNING *;
+
+-- name: RevokeGroupInvite :exec
+UPDATE group_invites
+SET revoked = TRUE
+WHERE code = $1;
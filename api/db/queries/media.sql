@@ -1,30 +1,49 @@
 -- name: GetImages :many
 SELECT *
 FROM images
-WHERE post_id = $1;
+WHERE post_id = $1
+ORDER BY position, id;
+
+-- name: GetImagesForPosts :many
+SELECT *
+FROM images
+WHERE post_id = ANY($1::uuid[])
+ORDER BY post_id, position, id;
 
 -- name: CreateImage :one
 INSERT INTO images (
     id,
     post_id,
-    media_url
+    media_url,
+    position,
+    width,
+    height,
+    blurhash
 )
-VALUES ($1, $2, $3)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
 ON CONFLICT (id) DO NOTHING
 RETURNING *;
 
 -- name: GetVideos :many
 SELECT *
 FROM videos
-WHERE post_id = $1;
+WHERE post_id = $1
+ORDER BY position, id;
+
+-- name: GetVideosForPosts :many
+SELECT *
+FROM videos
+WHERE post_id = ANY($1::uuid[])
+ORDER BY post_id, position, id;
 
 -- name: CreateVideo :one
 INSERT INTO videos (
     id,
     post_id,
-    media_url
+    media_url,
+    position
 )
-VALUES ($1, $2, $3)
+VALUES ($1, $2, $3, $4)
 ON CONFLICT (id) DO NOTHING
 RETURNING *;
 
@@ -33,6 +52,11 @@ SELECT *
 FROM links
 WHERE post_id = $1;
 
+-- name: GetLinksForPosts :many
+SELECT *
+FROM links
+WHERE post_id = ANY($1::uuid[]);
+
 -- name: CreateLink :one
 INSERT INTO links (
     id,
@@ -48,6 +72,11 @@ SELECT *
 FROM texts
 WHERE post_id = $1;
 
+-- name: GetTextsForPosts :many
+SELECT *
+FROM texts
+WHERE post_id = ANY($1::uuid[]);
+
 -- name: CreateText :one
 INSERT INTO texts (
     id,
@@ -39,6 +39,11 @@ DELETE FROM friend_group_posts
 WHERE group_id = $1
   AND post_id = $2;
 
+-- name: ListGroupsForPost :many
+SELECT group_id
+FROM friend_group_posts
+WHERE post_id = $1;
+
 -- name: ListPostsForGroup :many
 SELECT sqlc.embed(posts)
 FROM friend_group_posts fgp
@@ -50,6 +55,10 @@ SELECT sqlc.embed(posts)
 FROM friend_group_posts fgp
 JOIN posts ON posts.id = fgp.post_id
 WHERE fgp.group_id = $1 AND posts.status = 'PUBLISHED'
+  AND NOT EXISTS (
+      SELECT 1 FROM friendships f
+      WHERE f.user_id = posts.user_id AND f.friend_id = $3 AND f.status = 'BLOCKED'
+  )
 ORDER BY fgp.score DESC, fgp.post_id DESC
 LIMIT $2;
 
@@ -60,6 +69,10 @@ JOIN posts ON posts.id = fgp.post_id
 WHERE fgp.group_id = $1
   AND (fgp.score, fgp.post_id) < ($2, $3::uuid)
 AND posts.status = 'PUBLISHED'
+  AND NOT EXISTS (
+      SELECT 1 FROM friendships f
+      WHERE f.user_id = posts.user_id AND f.friend_id = $5 AND f.status = 'BLOCKED'
+  )
 ORDER BY fgp.score DESC, fgp.post_id DESC
 LIMIT $4;
 
@@ -82,20 +95,66 @@ SELECT EXISTS (
     LIMIT 1
 );
 
+-- name: CheckPostAuthorBlocked :one
+-- Whether the post's author has blocked the user.
+SELECT EXISTS (
+    SELECT 1
+    FROM posts
+    JOIN friendships ON friendships.user_id = posts.user_id
+    WHERE posts.id = sqlc.arg(post_id)
+      AND friendships.friend_id = sqlc.arg(user_id)
+      AND friendships.status = 'BLOCKED'
+);
+
 -- name: GetTopPost :one
 SELECT sqlc.embed(posts)
 FROM friend_group_posts fgp
 JOIN posts on fgp.post_id = posts.id
-WHERE fgp.group_id = $1 AND fgp.status = 'PUBLISHED'
+WHERE fgp.group_id = $1 AND posts.status = 'PUBLISHED'
+  AND NOT EXISTS (
+      SELECT 1 FROM friendships f
+      WHERE f.user_id = posts.user_id AND f.friend_id = $2 AND f.status = 'BLOCKED'
+  )
 ORDER BY fgp.score DESC
 LIMIT 1;
 
 -- name: UpdatePostScore :exec
 UPDATE friend_group_posts
 SET score = $2
-WHERE post_id = $1;
+WHERE post_id = $1 AND group_id = $3;
 
 -- name: UpdatePostStatus :exec
 UPDATE posts
 SET status = $2
 WHERE id = $1;
+
+-- name: LockPost :one
+SELECT status FROM posts
+WHERE id = $1
+FOR UPDATE;
+
+-- name: RecordPostView :exec
+INSERT INTO post_views (
+    post_id,
+    user_id,
+    date_viewed
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (post_id, user_id) DO UPDATE
+SET date_viewed = EXCLUDED.date_viewed;
+
+-- name: ListPostSignalsForGroup :many
+SELECT sqlc.embed(posts),
+    (SELECT COUNT(*) FROM post_views pv WHERE pv.post_id = posts.id)::bigint AS views,
+    (SELECT COUNT(*) FROM post_views pv WHERE pv.post_id = posts.id AND pv.date_viewed > @since::timestamptz)::bigint AS recent_views,
+    (SELECT COUNT(*) FROM post_reactions pr WHERE pr.post_id = posts.id)::bigint AS reactions,
+    (SELECT COUNT(*) FROM post_reactions pr WHERE pr.post_id = posts.id AND pr.date_created > @since::timestamptz)::bigint AS recent_reactions,
+    (SELECT COUNT(*) FROM comments c WHERE c.post_id = posts.id AND c.group_id = fgp.group_id)::bigint AS comments,
+    (SELECT COUNT(*) FROM comments c WHERE c.post_id = posts.id AND c.group_id = fgp.group_id AND c.date_created > @since::timestamptz)::bigint AS recent_comments
+FROM friend_group_posts fgp
+JOIN posts ON fgp.post_id = posts.id
+WHERE fgp.group_id = @group_id AND posts.status = 'PUBLISHED';
+
+-- name: CheckPostInGroup :one
+SELECT EXISTS(SELECT 1 FROM friend_group_posts WHERE group_id = $1 AND post_id = $2);
@@ -0,0 +1,32 @@
+-- name: AddReaction :execrows
+INSERT INTO post_reactions (
+    post_id,
+    user_id,
+    reaction,
+    date_created
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (post_id, user_id, reaction) DO NOTHING;
+
+-- name: RemoveReaction :exec
+DELETE FROM post_reactions
+WHERE post_id = $1
+  AND user_id = $2
+  AND reaction = $3;
+
+-- name: ListPostReactions :many
+SELECT *
+FROM post_reactions
+WHERE post_id = $1
+ORDER BY date_created;
+
+-- name: ListReactionCountsForPosts :many
+SELECT
+    post_id,
+    reaction,
+    COUNT(*)::bigint AS count
+FROM post_reactions
+WHERE post_id = ANY($1::uuid[])
+GROUP BY post_id, reaction
+ORDER BY post_id, count DESC, reaction;
@@ -0,0 +1,13 @@
+-- name: AcquireScoreLease :one
+INSERT INTO score_leases (
+    group_id,
+    holder,
+    expires_at
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (group_id) DO UPDATE
+SET holder = EXCLUDED.holder,
+    expires_at = EXCLUDED.expires_at
+WHERE score_leases.expires_at <= now()
+RETURNING group_id, holder, expires_at;
@@ -0,0 +1,52 @@
+-- name: CreateUploadSlot :one
+INSERT INTO upload_slots (
+    id,
+    post_id,
+    object_key,
+    content_type,
+    size,
+    date_created,
+    expires_at,
+    upload_id,
+    part_size,
+    position
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+RETURNING *;
+
+-- name: GetUploadSlot :one
+SELECT * FROM upload_slots
+WHERE id = $1;
+
+-- name: ListUploadSlotsForPost :many
+SELECT * FROM upload_slots
+WHERE post_id = $1
+ORDER BY position, date_created, id;
+
+-- name: TouchUploadSlot :exec
+UPDATE upload_slots
+SET expires_at = $2
+WHERE id = $1;
+
+-- name: MarkUploadSlotAssembled :exec
+UPDATE upload_slots
+SET upload_id = NULL
+WHERE id = $1;
+
+-- name: ClaimUploadSlotsForPost :many
+-- Deleting the slots claims them, so only one completion can turn them
+-- into media rows.
+DELETE FROM upload_slots
+WHERE post_id = $1
+RETURNING *;
+
+-- name: ListExpiredUploadSlots :many
+SELECT * FROM upload_slots
+WHERE expires_at < $1
+ORDER BY expires_at
+LIMIT $2;
+
+-- name: DeleteUploadSlot :exec
+DELETE FROM upload_slots
+WHERE id = $1;
@@ -91,3 +91,8 @@ WHERE id = $2;
 UPDATE user_profiles
 SET posts = posts + 1
 WHERE user_id = $1;
+
+-- name: DecrementPostCount :exec
+UPDATE user_profiles
+SET posts = GREATEST(posts - 1, 0)
+WHERE user_id = $1;
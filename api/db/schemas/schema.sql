@@ -3,7 +3,8 @@ CREATE TYPE media_type AS ENUM (
     'VIDEO',
     'LINK',
     'TEXT',
-    'OTHER'
+    'OTHER',
+    'CAROUSEL'
 );
 
 CREATE TYPE post_status AS ENUM (
@@ -52,13 +53,22 @@ CREATE TABLE user_profiles (
 CREATE TABLE images (
     id              UUID            PRIMARY KEY,
     post_id         UUID            NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
-    media_url  		TEXT            NOT NULL
+    media_url  		TEXT            NOT NULL,
+    -- Order of the item within a carousel post.
+    position        INTEGER         NOT NULL DEFAULT 0,
+    -- Upright dimensions of the full-size rendition.
+    width           INTEGER         NOT NULL DEFAULT 0,
+    height          INTEGER         NOT NULL DEFAULT 0,
+    -- Placeholder clients show while the image loads.
+    blurhash        TEXT
 );
 
 CREATE TABLE videos (
     id              UUID            PRIMARY KEY,
     post_id         UUID            NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
-    media_url  		TEXT            NOT NULL
+    media_url  		TEXT            NOT NULL,
+    -- Order of the item within a carousel post.
+    position        INTEGER         NOT NULL DEFAULT 0
 );
 
 CREATE TABLE links (
@@ -114,3 +124,112 @@ CREATE TABLE friend_group_posts (
     score   DECIMAL NOT NULL DEFAULT 0,
     PRIMARY KEY (group_id, post_id)
 );
+
+CREATE TABLE post_views (
+    post_id     UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id     UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    date_viewed TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (post_id, user_id)
+);
+
+CREATE TABLE score_leases (
+    group_id    UUID PRIMARY KEY REFERENCES friend_groups(id) ON DELETE CASCADE,
+    holder      TEXT NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE post_reactions (
+    post_id       UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id       UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reaction      TEXT NOT NULL,
+    date_created  TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (post_id, user_id, reaction)
+);
+
+CREATE TABLE comments (
+    id            UUID PRIMARY KEY,
+    post_id       UUID NOT NULL,
+    group_id      UUID NOT NULL,
+    user_id       UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    parent_id     UUID REFERENCES comments(id) ON DELETE CASCADE,
+    content       TEXT NOT NULL,
+    date_created  TIMESTAMPTZ NOT NULL,
+    date_updated  TIMESTAMPTZ,
+    -- Comments are scoped to the group the post was shared to.
+    FOREIGN KEY (group_id, post_id) REFERENCES friend_group_posts(group_id, post_id) ON DELETE CASCADE
+);
+
+CREATE TABLE group_invites (
+    code          TEXT PRIMARY KEY,
+    group_id      UUID NOT NULL REFERENCES friend_groups(id) ON DELETE CASCADE,
+    created_by    UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    date_created  TIMESTAMPTZ NOT NULL,
+    expires_at    TIMESTAMPTZ,
+    -- NULL means the invite can be used any number of times.
+    max_uses      INTEGER,
+    uses          INTEGER NOT NULL DEFAULT 0,
+    revoked       BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE group_event_sequences (
+    group_id       UUID PRIMARY KEY REFERENCES friend_groups(id) ON DELETE CASCADE,
+    last_sequence  BIGINT NOT NULL
+);
+
+-- Events sent to a single user are numbered per user. They aren't retained,
+-- the sequence only lets clients order them and spot gaps.
+CREATE TABLE user_event_sequences (
+    user_id        UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    last_sequence  BIGINT NOT NULL
+);
+
+-- Recently published realtime events, kept so reconnecting clients can replay
+-- what they missed. Old rows are pruned by the API.
+CREATE TABLE group_events (
+    group_id      UUID NOT NULL REFERENCES friend_groups(id) ON DELETE CASCADE,
+    sequence      BIGINT NOT NULL,
+    id            UUID NOT NULL,
+    payload       JSONB NOT NULL,
+    date_created  TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (group_id, sequence)
+);
+
+CREATE TABLE direct_messages (
+    id            UUID PRIMARY KEY,
+    sender_id     UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    recipient_id  UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    content       TEXT NOT NULL,
+    date_created  TIMESTAMPTZ NOT NULL,
+    -- Set once the recipient has read the message.
+    date_read     TIMESTAMPTZ
+);
+
+-- Which replicas hold a websocket for a user. Rows are refreshed while the
+-- socket stays open and expire if a replica dies without cleaning up.
+CREATE TABLE user_connections (
+    user_id     UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    replica     TEXT NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, replica)
+);
+
+-- A presigned upload the client was handed for a PENDING post. Slots are
+-- turned into images or videos rows once the upload is completed.
+CREATE TABLE upload_slots (
+    id            UUID PRIMARY KEY,
+    post_id       UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    object_key    TEXT NOT NULL,
+    content_type  TEXT NOT NULL,
+    size          BIGINT NOT NULL,
+    date_created  TIMESTAMPTZ NOT NULL,
+    -- When the presigned URL stops working. Resumable uploads push this
+    -- back whenever the client asks for more part URLs.
+    expires_at    TIMESTAMPTZ NOT NULL,
+    -- Set while a resumable upload is still collecting parts and cleared
+    -- once they have been assembled into the object.
+    upload_id     TEXT,
+    part_size     BIGINT,
+    -- Where the uploaded item goes in a carousel post.
+    position      INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (post_id, position)
+);
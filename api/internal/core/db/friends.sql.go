@@ -9,8 +9,68 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const blockUser = `-- name: BlockUser :one
+INSERT INTO friendships (
+    user_id,
+    friend_id,
+    status,
+    date_created
+) VALUES (
+    $1, $2, 'BLOCKED', $3
+)
+ON CONFLICT (user_id, friend_id) DO UPDATE
+SET status = 'BLOCKED',
+    date_created = EXCLUDED.date_created
+RETURNING user_id, friend_id, status, date_created
+`
+
+type BlockUserParams struct {
+	UserID      uuid.UUID          `json:"userId"`
+	FriendID    uuid.UUID          `json:"friendId"`
+	DateCreated pgtype.Timestamptz `json:"dateCreated"`
+}
+
+func (q *Queries) BlockUser(ctx context.Context, arg BlockUserParams) (Friendship, error) {
+	row := q.db.QueryRow(ctx, blockUser, arg.UserID, arg.FriendID, arg.DateCreated)
+	var i Friendship
+	err := row.Scan(
+		&i.UserID,
+		&i.FriendID,
+		&i.Status,
+		&i.DateCreated,
+	)
+	return i, err
+}
+
+const checkBlockedInGroup = `-- name: CheckBlockedInGroup :one
+SELECT EXISTS (
+    SELECT 1
+    FROM friendships
+    JOIN friend_group_members
+      ON friend_group_members.user_id IN (friendships.user_id, friendships.friend_id)
+    WHERE friendships.status = 'BLOCKED'
+      AND (friendships.user_id = $1 OR friendships.friend_id = $1)
+      AND friend_group_members.group_id = $2
+      AND friend_group_members.user_id <> $1
+)
+`
+
+type CheckBlockedInGroupParams struct {
+	UserID  uuid.UUID `json:"userId"`
+	GroupID uuid.UUID `json:"groupId"`
+}
+
+// Whether the user has blocked, or been blocked by, anyone in the group.
+func (q *Queries) CheckBlockedInGroup(ctx context.Context, arg CheckBlockedInGroupParams) (bool, error) {
+	row := q.db.QueryRow(ctx, checkBlockedInGroup, arg.UserID, arg.GroupID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
 const createFriendship = `-- name: CreateFriendship :one
 INSERT INTO friendships (
     user_id,
@@ -57,6 +117,22 @@ func (q *Queries) DeleteFriendship(ctx context.Context, arg DeleteFriendshipPara
 	return err
 }
 
+const deleteFriendshipsBetween = `-- name: DeleteFriendshipsBetween :exec
+DELETE FROM friendships
+WHERE (user_id = $1 AND friend_id = $2)
+   OR (user_id = $2 AND friend_id = $1)
+`
+
+type DeleteFriendshipsBetweenParams struct {
+	UserID   uuid.UUID `json:"userId"`
+	FriendID uuid.UUID `json:"friendId"`
+}
+
+func (q *Queries) DeleteFriendshipsBetween(ctx context.Context, arg DeleteFriendshipsBetweenParams) error {
+	_, err := q.db.Exec(ctx, deleteFriendshipsBetween, arg.UserID, arg.FriendID)
+	return err
+}
+
 const getFriendship = `-- name: GetFriendship :one
 SELECT
     user_id,
@@ -85,6 +161,137 @@ func (q *Queries) GetFriendship(ctx context.Context, arg GetFriendshipParams) (F
 	return i, err
 }
 
+const getFriendshipBetween = `-- name: GetFriendshipBetween :one
+SELECT
+    user_id,
+    friend_id,
+    status,
+    date_created
+FROM friendships
+WHERE (user_id = $1 AND friend_id = $2)
+   OR (user_id = $2 AND friend_id = $1)
+ORDER BY status DESC
+LIMIT 1
+`
+
+type GetFriendshipBetweenParams struct {
+	UserID   uuid.UUID `json:"userId"`
+	FriendID uuid.UUID `json:"friendId"`
+}
+
+// Friendships are stored once, in the direction the request was sent, so
+// look the pair up both ways. A block wins over anything else.
+func (q *Queries) GetFriendshipBetween(ctx context.Context, arg GetFriendshipBetweenParams) (Friendship, error) {
+	row := q.db.QueryRow(ctx, getFriendshipBetween, arg.UserID, arg.FriendID)
+	var i Friendship
+	err := row.Scan(
+		&i.UserID,
+		&i.FriendID,
+		&i.Status,
+		&i.DateCreated,
+	)
+	return i, err
+}
+
+const listBlockedUserIDs = `-- name: ListBlockedUserIDs :many
+SELECT friend_id FROM friendships
+WHERE user_id = $1 AND status = 'BLOCKED'
+`
+
+// Users the given user has blocked.
+func (q *Queries) ListBlockedUserIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, listBlockedUserIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var friend_id uuid.UUID
+		if err := rows.Scan(&friend_id); err != nil {
+			return nil, err
+		}
+		items = append(items, friend_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBlockerIDs = `-- name: ListBlockerIDs :many
+SELECT user_id FROM friendships
+WHERE friend_id = $1 AND status = 'BLOCKED'
+`
+
+// Users who have blocked the given user.
+func (q *Queries) ListBlockerIDs(ctx context.Context, friendID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, listBlockerIDs, friendID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var user_id uuid.UUID
+		if err := rows.Scan(&user_id); err != nil {
+			return nil, err
+		}
+		items = append(items, user_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFriendshipsWithProfiles = `-- name: ListFriendshipsWithProfiles :many
+SELECT friendships.user_id, friendships.friend_id, friendships.status, friendships.date_created, user_profiles.user_id, user_profiles.profile_pic, user_profiles.username, user_profiles.name, user_profiles.posts, user_profiles.date_created
+FROM friendships
+JOIN user_profiles ON user_profiles.user_id = CASE
+    WHEN friendships.user_id = $1 THEN friendships.friend_id
+    ELSE friendships.user_id
+END
+WHERE friendships.user_id = $1 OR friendships.friend_id = $1
+ORDER BY user_profiles.username
+`
+
+type ListFriendshipsWithProfilesRow struct {
+	Friendship  Friendship  `json:"friendship"`
+	UserProfile UserProfile `json:"userProfile"`
+}
+
+func (q *Queries) ListFriendshipsWithProfiles(ctx context.Context, userID uuid.UUID) ([]ListFriendshipsWithProfilesRow, error) {
+	rows, err := q.db.Query(ctx, listFriendshipsWithProfiles, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListFriendshipsWithProfilesRow
+	for rows.Next() {
+		var i ListFriendshipsWithProfilesRow
+		if err := rows.Scan(
+			&i.Friendship.UserID,
+			&i.Friendship.FriendID,
+			&i.Friendship.Status,
+			&i.Friendship.DateCreated,
+			&i.UserProfile.UserID,
+			&i.UserProfile.ProfilePic,
+			&i.UserProfile.Username,
+			&i.UserProfile.Name,
+			&i.UserProfile.Posts,
+			&i.UserProfile.DateCreated,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listUserFriendships = `-- name: ListUserFriendships :many
 SELECT
     user_id,
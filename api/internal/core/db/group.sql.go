@@ -241,6 +241,34 @@ func (q *Queries) GetFriendGroupsByIDs(ctx context.Context, dollar_1 []uuid.UUID
 	return items, nil
 }
 
+const getGroupMember = `-- name: GetGroupMember :one
+SELECT
+    group_id,
+    user_id,
+    joined_at,
+    role
+FROM friend_group_members
+WHERE group_id = $1
+  AND user_id = $2
+`
+
+type GetGroupMemberParams struct {
+	GroupID uuid.UUID `json:"groupId"`
+	UserID  uuid.UUID `json:"userId"`
+}
+
+func (q *Queries) GetGroupMember(ctx context.Context, arg GetGroupMemberParams) (FriendGroupMember, error) {
+	row := q.db.QueryRow(ctx, getGroupMember, arg.GroupID, arg.UserID)
+	var i FriendGroupMember
+	err := row.Scan(
+		&i.GroupID,
+		&i.UserID,
+		&i.JoinedAt,
+		&i.Role,
+	)
+	return i, err
+}
+
 const listFriendGroups = `-- name: ListFriendGroups :many
 SELECT
     id,
@@ -429,3 +457,53 @@ func (q *Queries) UpdateFriendGroupName(ctx context.Context, arg UpdateFriendGro
 	)
 	return i, err
 }
+
+const updateFriendGroupOwner = `-- name: UpdateFriendGroupOwner :one
+UPDATE friend_groups
+SET owner_id = $2
+WHERE id = $1
+RETURNING id, name, date_created, owner_id
+`
+
+type UpdateFriendGroupOwnerParams struct {
+	ID      uuid.UUID `json:"id"`
+	OwnerID uuid.UUID `json:"ownerId"`
+}
+
+func (q *Queries) UpdateFriendGroupOwner(ctx context.Context, arg UpdateFriendGroupOwnerParams) (FriendGroup, error) {
+	row := q.db.QueryRow(ctx, updateFriendGroupOwner, arg.ID, arg.OwnerID)
+	var i FriendGroup
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.DateCreated,
+		&i.OwnerID,
+	)
+	return i, err
+}
+
+const updateMemberRole = `-- name: UpdateMemberRole :one
+UPDATE friend_group_members
+SET role = $3
+WHERE group_id = $1
+  AND user_id = $2
+RETURNING group_id, user_id, joined_at, role
+`
+
+type UpdateMemberRoleParams struct {
+	GroupID uuid.UUID `json:"groupId"`
+	UserID  uuid.UUID `json:"userId"`
+	Role    GroupRole `json:"role"`
+}
+
+func (q *Queries) UpdateMemberRole(ctx context.Context, arg UpdateMemberRoleParams) (FriendGroupMember, error) {
+	row := q.db.QueryRow(ctx, updateMemberRole, arg.GroupID, arg.UserID, arg.Role)
+	var i FriendGroupMember
+	err := row.Scan(
+		&i.GroupID,
+		&i.UserID,
+		&i.JoinedAt,
+		&i.Role,
+	)
+	return i, err
+}
@@ -15,23 +15,47 @@ const createImage = `-- name: CreateImage :one
 INSERT INTO images (
     id,
     post_id,
-    media_url
+    media_url,
+    position,
+    width,
+    height,
+    blurhash
 )
-VALUES ($1, $2, $3)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
 ON CONFLICT (id) DO NOTHING
-RETURNING id, post_id, media_url
+RETURNING id, post_id, media_url, position, width, height, blurhash
 `
 
 type CreateImageParams struct {
 	ID       uuid.UUID `json:"id"`
 	PostID   uuid.UUID `json:"postId"`
 	MediaUrl string    `json:"mediaUrl"`
+	Position int32     `json:"position"`
+	Width    int32     `json:"width"`
+	Height   int32     `json:"height"`
+	Blurhash *string   `json:"blurhash"`
 }
 
 func (q *Queries) CreateImage(ctx context.Context, arg CreateImageParams) (Image, error) {
-	row := q.db.QueryRow(ctx, createImage, arg.ID, arg.PostID, arg.MediaUrl)
+	row := q.db.QueryRow(ctx, createImage,
+		arg.ID,
+		arg.PostID,
+		arg.MediaUrl,
+		arg.Position,
+		arg.Width,
+		arg.Height,
+		arg.Blurhash,
+	)
 	var i Image
-	err := row.Scan(&i.ID, &i.PostID, &i.MediaUrl)
+	err := row.Scan(
+		&i.ID,
+		&i.PostID,
+		&i.MediaUrl,
+		&i.Position,
+		&i.Width,
+		&i.Height,
+		&i.Blurhash,
+	)
 	return i, err
 }
 
@@ -87,30 +111,43 @@ const createVideo = `-- name: CreateVideo :one
 INSERT INTO videos (
     id,
     post_id,
-    media_url
+    media_url,
+    position
 )
-VALUES ($1, $2, $3)
+VALUES ($1, $2, $3, $4)
 ON CONFLICT (id) DO NOTHING
-RETURNING id, post_id, media_url
+RETURNING id, post_id, media_url, position
 `
 
 type CreateVideoParams struct {
 	ID       uuid.UUID `json:"id"`
 	PostID   uuid.UUID `json:"postId"`
 	MediaUrl string    `json:"mediaUrl"`
+	Position int32     `json:"position"`
 }
 
 func (q *Queries) CreateVideo(ctx context.Context, arg CreateVideoParams) (Video, error) {
-	row := q.db.QueryRow(ctx, createVideo, arg.ID, arg.PostID, arg.MediaUrl)
+	row := q.db.QueryRow(ctx, createVideo,
+		arg.ID,
+		arg.PostID,
+		arg.MediaUrl,
+		arg.Position,
+	)
 	var i Video
-	err := row.Scan(&i.ID, &i.PostID, &i.MediaUrl)
+	err := row.Scan(
+		&i.ID,
+		&i.PostID,
+		&i.MediaUrl,
+		&i.Position,
+	)
 	return i, err
 }
 
 const getImages = `-- name: GetImages :many
-SELECT id, post_id, media_url
+SELECT id, post_id, media_url, position, width, height, blurhash
 FROM images
 WHERE post_id = $1
+ORDER BY position, id
 `
 
 func (q *Queries) GetImages(ctx context.Context, postID uuid.UUID) ([]Image, error) {
@@ -122,7 +159,50 @@ func (q *Queries) GetImages(ctx context.Context, postID uuid.UUID) ([]Image, err
 	var items []Image
 	for rows.Next() {
 		var i Image
-		if err := rows.Scan(&i.ID, &i.PostID, &i.MediaUrl); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.PostID,
+			&i.MediaUrl,
+			&i.Position,
+			&i.Width,
+			&i.Height,
+			&i.Blurhash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getImagesForPosts = `-- name: GetImagesForPosts :many
+SELECT id, post_id, media_url, position, width, height, blurhash
+FROM images
+WHERE post_id = ANY($1::uuid[])
+ORDER BY post_id, position, id
+`
+
+func (q *Queries) GetImagesForPosts(ctx context.Context, dollar_1 []uuid.UUID) ([]Image, error) {
+	rows, err := q.db.Query(ctx, getImagesForPosts, dollar_1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Image
+	for rows.Next() {
+		var i Image
+		if err := rows.Scan(
+			&i.ID,
+			&i.PostID,
+			&i.MediaUrl,
+			&i.Position,
+			&i.Width,
+			&i.Height,
+			&i.Blurhash,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -159,6 +239,32 @@ func (q *Queries) GetLinks(ctx context.Context, postID uuid.UUID) ([]Link, error
 	return items, nil
 }
 
+const getLinksForPosts = `-- name: GetLinksForPosts :many
+SELECT id, post_id, media_url
+FROM links
+WHERE post_id = ANY($1::uuid[])
+`
+
+func (q *Queries) GetLinksForPosts(ctx context.Context, dollar_1 []uuid.UUID) ([]Link, error) {
+	rows, err := q.db.Query(ctx, getLinksForPosts, dollar_1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Link
+	for rows.Next() {
+		var i Link
+		if err := rows.Scan(&i.ID, &i.PostID, &i.MediaUrl); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTexts = `-- name: GetTexts :many
 SELECT id, post_id, text
 FROM texts
@@ -185,10 +291,37 @@ func (q *Queries) GetTexts(ctx context.Context, postID uuid.UUID) ([]Text, error
 	return items, nil
 }
 
+const getTextsForPosts = `-- name: GetTextsForPosts :many
+SELECT id, post_id, text
+FROM texts
+WHERE post_id = ANY($1::uuid[])
+`
+
+func (q *Queries) GetTextsForPosts(ctx context.Context, dollar_1 []uuid.UUID) ([]Text, error) {
+	rows, err := q.db.Query(ctx, getTextsForPosts, dollar_1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Text
+	for rows.Next() {
+		var i Text
+		if err := rows.Scan(&i.ID, &i.PostID, &i.Text); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getVideos = `-- name: GetVideos :many
-SELECT id, post_id, media_url
+SELECT id, post_id, media_url, position
 FROM videos
 WHERE post_id = $1
+ORDER BY position, id
 `
 
 func (q *Queries) GetVideos(ctx context.Context, postID uuid.UUID) ([]Video, error) {
@@ -200,7 +333,44 @@ func (q *Queries) GetVideos(ctx context.Context, postID uuid.UUID) ([]Video, err
 	var items []Video
 	for rows.Next() {
 		var i Video
-		if err := rows.Scan(&i.ID, &i.PostID, &i.MediaUrl); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.PostID,
+			&i.MediaUrl,
+			&i.Position,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getVideosForPosts = `-- name: GetVideosForPosts :many
+SELECT id, post_id, media_url, position
+FROM videos
+WHERE post_id = ANY($1::uuid[])
+ORDER BY post_id, position, id
+`
+
+func (q *Queries) GetVideosForPosts(ctx context.Context, dollar_1 []uuid.UUID) ([]Video, error) {
+	rows, err := q.db.Query(ctx, getVideosForPosts, dollar_1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Video
+	for rows.Next() {
+		var i Video
+		if err := rows.Scan(
+			&i.ID,
+			&i.PostID,
+			&i.MediaUrl,
+			&i.Position,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -100,11 +100,12 @@ func (ns NullGroupRole) Value() (driver.Value, error) {
 type MediaType string
 
 const (
-	MediaTypeIMAGE MediaType = "IMAGE"
-	MediaTypeVIDEO MediaType = "VIDEO"
-	MediaTypeLINK  MediaType = "LINK"
-	MediaTypeTEXT  MediaType = "TEXT"
-	MediaTypeOTHER MediaType = "OTHER"
+	MediaTypeIMAGE    MediaType = "IMAGE"
+	MediaTypeVIDEO    MediaType = "VIDEO"
+	MediaTypeLINK     MediaType = "LINK"
+	MediaTypeTEXT     MediaType = "TEXT"
+	MediaTypeOTHER    MediaType = "OTHER"
+	MediaTypeCAROUSEL MediaType = "CAROUSEL"
 )
 
 func (e *MediaType) Scan(src interface{}) error {
@@ -230,6 +231,26 @@ func (ns NullProviderType) Value() (driver.Value, error) {
 	return string(ns.ProviderType), nil
 }
 
+type Comment struct {
+	ID          uuid.UUID          `json:"id"`
+	PostID      uuid.UUID          `json:"postId"`
+	GroupID     uuid.UUID          `json:"groupId"`
+	UserID      uuid.UUID          `json:"userId"`
+	ParentID    *uuid.UUID         `json:"parentId"`
+	Content     string             `json:"content"`
+	DateCreated pgtype.Timestamptz `json:"dateCreated"`
+	DateUpdated pgtype.Timestamptz `json:"dateUpdated"`
+}
+
+type DirectMessage struct {
+	ID          uuid.UUID          `json:"id"`
+	SenderID    uuid.UUID          `json:"senderId"`
+	RecipientID uuid.UUID          `json:"recipientId"`
+	Content     string             `json:"content"`
+	DateCreated pgtype.Timestamptz `json:"dateCreated"`
+	DateRead    pgtype.Timestamptz `json:"dateRead"`
+}
+
 type FriendGroup struct {
 	ID          uuid.UUID          `json:"id"`
 	Name        string             `json:"name"`
@@ -257,10 +278,38 @@ type Friendship struct {
 	DateCreated pgtype.Timestamptz `json:"dateCreated"`
 }
 
+type GroupEvent struct {
+	GroupID     uuid.UUID          `json:"groupId"`
+	Sequence    int64              `json:"sequence"`
+	ID          uuid.UUID          `json:"id"`
+	Payload     []byte             `json:"payload"`
+	DateCreated pgtype.Timestamptz `json:"dateCreated"`
+}
+
+type GroupEventSequence struct {
+	GroupID      uuid.UUID `json:"groupId"`
+	LastSequence int64     `json:"lastSequence"`
+}
+
+type GroupInvite struct {
+	Code        string             `json:"code"`
+	GroupID     uuid.UUID          `json:"groupId"`
+	CreatedBy   uuid.UUID          `json:"createdBy"`
+	DateCreated pgtype.Timestamptz `json:"dateCreated"`
+	ExpiresAt   pgtype.Timestamptz `json:"expiresAt"`
+	MaxUses     *int32             `json:"maxUses"`
+	Uses        int32              `json:"uses"`
+	Revoked     bool               `json:"revoked"`
+}
+
 type Image struct {
 	ID       uuid.UUID `json:"id"`
 	PostID   uuid.UUID `json:"postId"`
 	MediaUrl string    `json:"mediaUrl"`
+	Position int32     `json:"position"`
+	Width    int32     `json:"width"`
+	Height   int32     `json:"height"`
+	Blurhash *string   `json:"blurhash"`
 }
 
 type Link struct {
@@ -278,12 +327,44 @@ type Post struct {
 	Status      PostStatus         `json:"status"`
 }
 
+type PostReaction struct {
+	PostID      uuid.UUID          `json:"postId"`
+	UserID      uuid.UUID          `json:"userId"`
+	Reaction    string             `json:"reaction"`
+	DateCreated pgtype.Timestamptz `json:"dateCreated"`
+}
+
+type PostView struct {
+	PostID     uuid.UUID          `json:"postId"`
+	UserID     uuid.UUID          `json:"userId"`
+	DateViewed pgtype.Timestamptz `json:"dateViewed"`
+}
+
+type ScoreLease struct {
+	GroupID   uuid.UUID          `json:"groupId"`
+	Holder    string             `json:"holder"`
+	ExpiresAt pgtype.Timestamptz `json:"expiresAt"`
+}
+
 type Text struct {
 	ID     uuid.UUID `json:"id"`
 	PostID uuid.UUID `json:"postId"`
 	Text   string    `json:"text"`
 }
 
+type UploadSlot struct {
+	ID          uuid.UUID          `json:"id"`
+	PostID      uuid.UUID          `json:"postId"`
+	ObjectKey   string             `json:"objectKey"`
+	ContentType string             `json:"contentType"`
+	Size        int64              `json:"size"`
+	DateCreated pgtype.Timestamptz `json:"dateCreated"`
+	ExpiresAt   pgtype.Timestamptz `json:"expiresAt"`
+	UploadID    *string            `json:"uploadId"`
+	PartSize    *int64             `json:"partSize"`
+	Position    int32              `json:"position"`
+}
+
 type User struct {
 	ID           uuid.UUID          `json:"id"`
 	FirebaseUid  string             `json:"firebaseUid"`
@@ -295,6 +376,17 @@ type User struct {
 	DeviceTokens []string           `json:"deviceTokens"`
 }
 
+type UserConnection struct {
+	UserID    uuid.UUID          `json:"userId"`
+	Replica   string             `json:"replica"`
+	ExpiresAt pgtype.Timestamptz `json:"expiresAt"`
+}
+
+type UserEventSequence struct {
+	UserID       uuid.UUID `json:"userId"`
+	LastSequence int64     `json:"lastSequence"`
+}
+
 type UserProfile struct {
 	UserID      uuid.UUID          `json:"userId"`
 	ProfilePic  *string            `json:"profilePic"`
@@ -308,4 +400,5 @@ type Video struct {
 	ID       uuid.UUID `json:"id"`
 	PostID   uuid.UUID `json:"postId"`
 	MediaUrl string    `json:"mediaUrl"`
+	Position int32     `json:"position"`
 }
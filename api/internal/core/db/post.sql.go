@@ -31,6 +31,46 @@ func (q *Queries) AddPostToFriendGroup(ctx context.Context, arg AddPostToFriendG
 	return err
 }
 
+const checkPostAuthorBlocked = `-- name: CheckPostAuthorBlocked :one
+SELECT EXISTS (
+    SELECT 1
+    FROM posts
+    JOIN friendships ON friendships.user_id = posts.user_id
+    WHERE posts.id = $1
+      AND friendships.friend_id = $2
+      AND friendships.status = 'BLOCKED'
+)
+`
+
+type CheckPostAuthorBlockedParams struct {
+	PostID uuid.UUID `json:"postId"`
+	UserID uuid.UUID `json:"userId"`
+}
+
+// Whether the post's author has blocked the user.
+func (q *Queries) CheckPostAuthorBlocked(ctx context.Context, arg CheckPostAuthorBlockedParams) (bool, error) {
+	row := q.db.QueryRow(ctx, checkPostAuthorBlocked, arg.PostID, arg.UserID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const checkPostInGroup = `-- name: CheckPostInGroup :one
+SELECT EXISTS(SELECT 1 FROM friend_group_posts WHERE group_id = $1 AND post_id = $2)
+`
+
+type CheckPostInGroupParams struct {
+	GroupID uuid.UUID `json:"groupId"`
+	PostID  uuid.UUID `json:"postId"`
+}
+
+func (q *Queries) CheckPostInGroup(ctx context.Context, arg CheckPostInGroupParams) (bool, error) {
+	row := q.db.QueryRow(ctx, checkPostInGroup, arg.GroupID, arg.PostID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
 const checkPostOwner = `-- name: CheckPostOwner :one
 SELECT EXISTS(SELECT 1 FROM posts WHERE user_id = $1 and id = $2)
 `
@@ -192,17 +232,26 @@ const getTopPost = `-- name: GetTopPost :one
 SELECT posts.id, posts.user_id, posts.media, posts.date_created, posts.caption, posts.status
 FROM friend_group_posts fgp
 JOIN posts on fgp.post_id = posts.id
-WHERE fgp.group_id = $1 AND fgp.status = 'PUBLISHED'
+WHERE fgp.group_id = $1 AND posts.status = 'PUBLISHED'
+  AND NOT EXISTS (
+      SELECT 1 FROM friendships f
+      WHERE f.user_id = posts.user_id AND f.friend_id = $2 AND f.status = 'BLOCKED'
+  )
 ORDER BY fgp.score DESC
 LIMIT 1
 `
 
+type GetTopPostParams struct {
+	GroupID  uuid.UUID `json:"groupId"`
+	FriendID uuid.UUID `json:"friendId"`
+}
+
 type GetTopPostRow struct {
 	Post Post `json:"post"`
 }
 
-func (q *Queries) GetTopPost(ctx context.Context, groupID uuid.UUID) (GetTopPostRow, error) {
-	row := q.db.QueryRow(ctx, getTopPost, groupID)
+func (q *Queries) GetTopPost(ctx context.Context, arg GetTopPostParams) (GetTopPostRow, error) {
+	row := q.db.QueryRow(ctx, getTopPost, arg.GroupID, arg.FriendID)
 	var i GetTopPostRow
 	err := row.Scan(
 		&i.Post.ID,
@@ -220,13 +269,18 @@ SELECT posts.id, posts.user_id, posts.media, posts.date_created, posts.caption,
 FROM friend_group_posts fgp
 JOIN posts ON posts.id = fgp.post_id
 WHERE fgp.group_id = $1 AND posts.status = 'PUBLISHED'
+  AND NOT EXISTS (
+      SELECT 1 FROM friendships f
+      WHERE f.user_id = posts.user_id AND f.friend_id = $3 AND f.status = 'BLOCKED'
+  )
 ORDER BY fgp.score DESC, fgp.post_id DESC
 LIMIT $2
 `
 
 type InitialPostsForGroupParams struct {
-	GroupID uuid.UUID `json:"groupId"`
-	Limit   int32     `json:"limit"`
+	GroupID  uuid.UUID `json:"groupId"`
+	Limit    int32     `json:"limit"`
+	FriendID uuid.UUID `json:"friendId"`
 }
 
 type InitialPostsForGroupRow struct {
@@ -234,7 +288,7 @@ type InitialPostsForGroupRow struct {
 }
 
 func (q *Queries) InitialPostsForGroup(ctx context.Context, arg InitialPostsForGroupParams) ([]InitialPostsForGroupRow, error) {
-	rows, err := q.db.Query(ctx, initialPostsForGroup, arg.GroupID, arg.Limit)
+	rows, err := q.db.Query(ctx, initialPostsForGroup, arg.GroupID, arg.Limit, arg.FriendID)
 	if err != nil {
 		return nil, err
 	}
@@ -260,6 +314,32 @@ func (q *Queries) InitialPostsForGroup(ctx context.Context, arg InitialPostsForG
 	return items, nil
 }
 
+const listGroupsForPost = `-- name: ListGroupsForPost :many
+SELECT group_id
+FROM friend_group_posts
+WHERE post_id = $1
+`
+
+func (q *Queries) ListGroupsForPost(ctx context.Context, postID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, listGroupsForPost, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var group_id uuid.UUID
+		if err := rows.Scan(&group_id); err != nil {
+			return nil, err
+		}
+		items = append(items, group_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPaginatedPostsForGroup = `-- name: ListPaginatedPostsForGroup :many
 SELECT posts.id, posts.user_id, posts.media, posts.date_created, posts.caption, posts.status
 FROM friend_group_posts fgp
@@ -267,15 +347,20 @@ JOIN posts ON posts.id = fgp.post_id
 WHERE fgp.group_id = $1
   AND (fgp.score, fgp.post_id) < ($2, $3::uuid)
 AND posts.status = 'PUBLISHED'
+  AND NOT EXISTS (
+      SELECT 1 FROM friendships f
+      WHERE f.user_id = posts.user_id AND f.friend_id = $5 AND f.status = 'BLOCKED'
+  )
 ORDER BY fgp.score DESC, fgp.post_id DESC
 LIMIT $4
 `
 
 type ListPaginatedPostsForGroupParams struct {
-	GroupID uuid.UUID      `json:"groupId"`
-	Score   pgtype.Numeric `json:"score"`
-	Column3 uuid.UUID      `json:"column3"`
-	Limit   int32          `json:"limit"`
+	GroupID  uuid.UUID      `json:"groupId"`
+	Score    pgtype.Numeric `json:"score"`
+	Column3  uuid.UUID      `json:"column3"`
+	Limit    int32          `json:"limit"`
+	FriendID uuid.UUID      `json:"friendId"`
 }
 
 type ListPaginatedPostsForGroupRow struct {
@@ -288,6 +373,7 @@ func (q *Queries) ListPaginatedPostsForGroup(ctx context.Context, arg ListPagina
 		arg.Score,
 		arg.Column3,
 		arg.Limit,
+		arg.FriendID,
 	)
 	if err != nil {
 		return nil, err
@@ -314,6 +400,67 @@ func (q *Queries) ListPaginatedPostsForGroup(ctx context.Context, arg ListPagina
 	return items, nil
 }
 
+const listPostSignalsForGroup = `-- name: ListPostSignalsForGroup :many
+SELECT posts.id, posts.user_id, posts.media, posts.date_created, posts.caption, posts.status,
+    (SELECT COUNT(*) FROM post_views pv WHERE pv.post_id = posts.id)::bigint AS views,
+    (SELECT COUNT(*) FROM post_views pv WHERE pv.post_id = posts.id AND pv.date_viewed > $1::timestamptz)::bigint AS recent_views,
+    (SELECT COUNT(*) FROM post_reactions pr WHERE pr.post_id = posts.id)::bigint AS reactions,
+    (SELECT COUNT(*) FROM post_reactions pr WHERE pr.post_id = posts.id AND pr.date_created > $1::timestamptz)::bigint AS recent_reactions,
+    (SELECT COUNT(*) FROM comments c WHERE c.post_id = posts.id AND c.group_id = fgp.group_id)::bigint AS comments,
+    (SELECT COUNT(*) FROM comments c WHERE c.post_id = posts.id AND c.group_id = fgp.group_id AND c.date_created > $1::timestamptz)::bigint AS recent_comments
+FROM friend_group_posts fgp
+JOIN posts ON fgp.post_id = posts.id
+WHERE fgp.group_id = $2 AND posts.status = 'PUBLISHED'
+`
+
+type ListPostSignalsForGroupParams struct {
+	Since   pgtype.Timestamptz `json:"since"`
+	GroupID uuid.UUID          `json:"groupId"`
+}
+
+type ListPostSignalsForGroupRow struct {
+	Post            Post  `json:"post"`
+	Views           int64 `json:"views"`
+	RecentViews     int64 `json:"recentViews"`
+	Reactions       int64 `json:"reactions"`
+	RecentReactions int64 `json:"recentReactions"`
+	Comments        int64 `json:"comments"`
+	RecentComments  int64 `json:"recentComments"`
+}
+
+func (q *Queries) ListPostSignalsForGroup(ctx context.Context, arg ListPostSignalsForGroupParams) ([]ListPostSignalsForGroupRow, error) {
+	rows, err := q.db.Query(ctx, listPostSignalsForGroup, arg.Since, arg.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPostSignalsForGroupRow
+	for rows.Next() {
+		var i ListPostSignalsForGroupRow
+		if err := rows.Scan(
+			&i.Post.ID,
+			&i.Post.UserID,
+			&i.Post.Media,
+			&i.Post.DateCreated,
+			&i.Post.Caption,
+			&i.Post.Status,
+			&i.Views,
+			&i.RecentViews,
+			&i.Reactions,
+			&i.RecentReactions,
+			&i.Comments,
+			&i.RecentComments,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPostsForGroup = `-- name: ListPostsForGroup :many
 SELECT posts.id, posts.user_id, posts.media, posts.date_created, posts.caption, posts.status
 FROM friend_group_posts fgp
@@ -352,6 +499,42 @@ func (q *Queries) ListPostsForGroup(ctx context.Context, groupID uuid.UUID) ([]L
 	return items, nil
 }
 
+const lockPost = `-- name: LockPost :one
+SELECT status FROM posts
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockPost(ctx context.Context, id uuid.UUID) (PostStatus, error) {
+	row := q.db.QueryRow(ctx, lockPost, id)
+	var status PostStatus
+	err := row.Scan(&status)
+	return status, err
+}
+
+const recordPostView = `-- name: RecordPostView :exec
+INSERT INTO post_views (
+    post_id,
+    user_id,
+    date_viewed
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (post_id, user_id) DO UPDATE
+SET date_viewed = EXCLUDED.date_viewed
+`
+
+type RecordPostViewParams struct {
+	PostID     uuid.UUID          `json:"postId"`
+	UserID     uuid.UUID          `json:"userId"`
+	DateViewed pgtype.Timestamptz `json:"dateViewed"`
+}
+
+func (q *Queries) RecordPostView(ctx context.Context, arg RecordPostViewParams) error {
+	_, err := q.db.Exec(ctx, recordPostView, arg.PostID, arg.UserID, arg.DateViewed)
+	return err
+}
+
 const removePostFromFriendGroup = `-- name: RemovePostFromFriendGroup :exec
 DELETE FROM friend_group_posts
 WHERE group_id = $1
@@ -396,16 +579,17 @@ func (q *Queries) UpdatePost(ctx context.Context, arg UpdatePostParams) error {
 const updatePostScore = `-- name: UpdatePostScore :exec
 UPDATE friend_group_posts
 SET score = $2
-WHERE post_id = $1
+WHERE post_id = $1 AND group_id = $3
 `
 
 type UpdatePostScoreParams struct {
-	PostID uuid.UUID      `json:"postId"`
-	Score  pgtype.Numeric `json:"score"`
+	PostID  uuid.UUID      `json:"postId"`
+	Score   pgtype.Numeric `json:"score"`
+	GroupID uuid.UUID      `json:"groupId"`
 }
 
 func (q *Queries) UpdatePostScore(ctx context.Context, arg UpdatePostScoreParams) error {
-	_, err := q.db.Exec(ctx, updatePostScore, arg.PostID, arg.Score)
+	_, err := q.db.Exec(ctx, updatePostScore, arg.PostID, arg.Score, arg.GroupID)
 	return err
 }
 
@@ -0,0 +1,33 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// beginner is implemented by both pgxpool.Pool and pgx.Tx, so InTx nests as
+// a savepoint when the queries already run in a transaction.
+type beginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// InTx runs fn with queries bound to a single transaction, committing it if
+// fn returns nil and rolling it back otherwise.
+func (q *Queries) InTx(ctx context.Context, fn func(*Queries) error) error {
+	db, ok := q.db.(beginner)
+	if !ok {
+		return errors.New("database connection can't begin transactions")
+	}
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(q.WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -135,6 +135,17 @@ func (q *Queries) CreateUserProfile(ctx context.Context, arg CreateUserProfilePa
 	return i, err
 }
 
+const decrementPostCount = `-- name: DecrementPostCount :exec
+UPDATE user_profiles
+SET posts = GREATEST(posts - 1, 0)
+WHERE user_id = $1
+`
+
+func (q *Queries) DecrementPostCount(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, decrementPostCount, userID)
+	return err
+}
+
 const deleteUser = `-- name: DeleteUser :exec
 DELETE FROM users
 WHERE id = $1
@@ -0,0 +1,64 @@
+package fetch
+
+import (
+	database "api/internal/core/db"
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// CarouselItem is one image or video of a carousel post. Exactly one of Image
+// and Video is set, matching Media.
+type CarouselItem struct {
+	Position int32              `json:"position"`
+	Media    database.MediaType `json:"media"`
+	Image    *database.Image    `json:"image,omitempty"`
+	Video    *database.Video    `json:"video,omitempty"`
+}
+
+// FetchCarouselItems returns the items of a single carousel post in order.
+func FetchCarouselItems(ctx context.Context, queries *database.Queries, postID uuid.UUID) ([]CarouselItem, error) {
+	items, err := FetchCarouselItemsForPosts(ctx, queries, []uuid.UUID{postID})
+	if err != nil {
+		return nil, err
+	}
+	return items[postID], nil
+}
+
+// FetchCarouselItemsForPosts loads the images and videos of several carousel
+// posts and interleaves them by position, keyed by post ID.
+func FetchCarouselItemsForPosts(ctx context.Context, queries *database.Queries, postIDs []uuid.UUID) (map[uuid.UUID][]CarouselItem, error) {
+	images, err := queries.GetImagesForPosts(ctx, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	videos, err := queries.GetVideosForPosts(ctx, postIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(map[uuid.UUID][]CarouselItem, len(postIDs))
+	for i := range images {
+		image := &images[i]
+		items[image.PostID] = append(items[image.PostID], CarouselItem{
+			Position: image.Position,
+			Media:    database.MediaTypeIMAGE,
+			Image:    image,
+		})
+	}
+	for i := range videos {
+		video := &videos[i]
+		items[video.PostID] = append(items[video.PostID], CarouselItem{
+			Position: video.Position,
+			Media:    database.MediaTypeVIDEO,
+			Video:    video,
+		})
+	}
+	for _, postItems := range items {
+		sort.SliceStable(postItems, func(i, j int) bool {
+			return postItems[i].Position < postItems[j].Position
+		})
+	}
+	return items, nil
+}
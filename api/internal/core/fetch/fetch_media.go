@@ -3,19 +3,69 @@ package fetch
 import (
 	database "api/internal/core/db"
 	"context"
+
+	"github.com/google/uuid"
 )
 
-func FetchMedia(ctx context.Context, queries *database.Queries, post database.Post) any {
-	var media any
-	switch post.Media {
-	case database.MediaTypeIMAGE:
-		media, _ = queries.GetImages(ctx, post.ID)
-	case database.MediaTypeVIDEO:
-		media, _ = queries.GetVideos(ctx, post.ID)
-	case database.MediaTypeLINK:
-		media, _ = queries.GetLinks(ctx, post.ID)
-	case database.MediaTypeTEXT:
-		media, _ = queries.GetTexts(ctx, post.ID)
-	}
-	return media
+// FetchMediaForPosts loads the media of every post with one query per media
+// type, keyed by post ID. Images and videos come back in position order and
+// carousel posts get their items as a []CarouselItem. Posts without any media
+// rows are left out of the map.
+func FetchMediaForPosts(ctx context.Context, queries *database.Queries, posts []database.Post) (map[uuid.UUID]any, error) {
+	postIDs := make(map[database.MediaType][]uuid.UUID)
+	for _, post := range posts {
+		postIDs[post.Media] = append(postIDs[post.Media], post.ID)
+	}
+
+	media := make(map[uuid.UUID]any, len(posts))
+	if ids := postIDs[database.MediaTypeIMAGE]; len(ids) > 0 {
+		images, err := queries.GetImagesForPosts(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		groupByPost(media, images, func(image database.Image) uuid.UUID { return image.PostID })
+	}
+	if ids := postIDs[database.MediaTypeVIDEO]; len(ids) > 0 {
+		videos, err := queries.GetVideosForPosts(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		groupByPost(media, videos, func(video database.Video) uuid.UUID { return video.PostID })
+	}
+	if ids := postIDs[database.MediaTypeLINK]; len(ids) > 0 {
+		links, err := queries.GetLinksForPosts(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		groupByPost(media, links, func(link database.Link) uuid.UUID { return link.PostID })
+	}
+	if ids := postIDs[database.MediaTypeTEXT]; len(ids) > 0 {
+		texts, err := queries.GetTextsForPosts(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		groupByPost(media, texts, func(text database.Text) uuid.UUID { return text.PostID })
+	}
+	if ids := postIDs[database.MediaTypeCAROUSEL]; len(ids) > 0 {
+		items, err := FetchCarouselItemsForPosts(ctx, queries, ids)
+		if err != nil {
+			return nil, err
+		}
+		for id, postItems := range items {
+			media[id] = postItems
+		}
+	}
+	return media, nil
+}
+
+// groupByPost collects rows into one slice per post so each post's media keeps
+// its concrete type when encoded.
+func groupByPost[T any](media map[uuid.UUID]any, rows []T, postID func(T) uuid.UUID) {
+	grouped := make(map[uuid.UUID][]T)
+	for _, row := range rows {
+		grouped[postID(row)] = append(grouped[postID(row)], row)
+	}
+	for id, items := range grouped {
+		media[id] = items
+	}
 }
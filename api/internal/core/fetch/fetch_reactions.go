@@ -0,0 +1,30 @@
+package fetch
+
+import (
+	database "api/internal/core/db"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type ReactionCount struct {
+	Reaction string `json:"reaction"`
+	Count    int64  `json:"count"`
+}
+
+// FetchReactionCounts loads the reaction totals for every post in one query,
+// keyed by post ID.
+func FetchReactionCounts(ctx context.Context, queries *database.Queries, postIDs []uuid.UUID) (map[uuid.UUID][]ReactionCount, error) {
+	rows, err := queries.ListReactionCountsForPosts(ctx, postIDs)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[uuid.UUID][]ReactionCount, len(postIDs))
+	for _, row := range rows {
+		counts[row.PostID] = append(counts[row.PostID], ReactionCount{
+			Reaction: row.Reaction,
+			Count:    row.Count,
+		})
+	}
+	return counts, nil
+}
@@ -0,0 +1,70 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/draw"
+
+	"github.com/rwcarlsen/goexif/exif"
+)
+
+// orientation reads the EXIF orientation of a JPEG, defaulting to 1 (upright)
+// when there is no EXIF data or it can't be parsed.
+func orientation(data []byte) int {
+	metadata, err := exif.Decode(bytes.NewReader(data))
+	if err != nil {
+		return 1
+	}
+	tag, err := metadata.Get(exif.Orientation)
+	if err != nil {
+		return 1
+	}
+	value, err := tag.Int(0)
+	if err != nil || value < 1 || value > 8 {
+		return 1
+	}
+	return value
+}
+
+// orient returns img turned upright according to an EXIF orientation value.
+func orient(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	src := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	// Orientations 5 to 8 swap the axes.
+	outWidth, outHeight := width, height
+	if orientation >= 5 {
+		outWidth, outHeight = height, width
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, outWidth, outHeight))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // Mirrored horizontally.
+				dx, dy = width-1-x, y
+			case 3: // Rotated 180°.
+				dx, dy = width-1-x, height-1-y
+			case 4: // Mirrored vertically.
+				dx, dy = x, height-1-y
+			case 5: // Mirrored along the top-left diagonal.
+				dx, dy = y, x
+			case 6: // Rotated 90° clockwise.
+				dx, dy = height-1-y, x
+			case 7: // Mirrored along the top-right diagonal.
+				dx, dy = height-1-y, width-1-x
+			case 8: // Rotated 90° counter-clockwise.
+				dx, dy = y, width-1-x
+			}
+			srcOffset := src.PixOffset(x, y)
+			dstOffset := dst.PixOffset(dx, dy)
+			copy(dst.Pix[dstOffset:dstOffset+4], src.Pix[srcOffset:srcOffset+4])
+		}
+	}
+	return dst
+}
@@ -0,0 +1,219 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"github.com/buckket/go-blurhash"
+	"github.com/gen2brain/heic"
+	"github.com/gen2brain/webp"
+	"golang.org/x/image/draw"
+)
+
+// MaxFileSize is the largest image file Process reads, which is also the
+// upload limit for images.
+const MaxFileSize = 50 << 20 // 50 MB
+
+// maxPixels rejects images that would take too much memory to decode.
+const maxPixels = 50_000_000
+
+var (
+	// ErrUnsupported is returned for files that aren't an image we can decode.
+	ErrUnsupported = errors.New("unsupported image format")
+	// ErrTooLarge is returned for files over MaxFileSize and images over
+	// maxPixels.
+	ErrTooLarge = errors.New("image too large")
+)
+
+// Size is a rendition size, bounded by its longest edge. Images are never
+// scaled up.
+type Size struct {
+	Name    string
+	MaxEdge int
+	Quality int
+}
+
+var (
+	Thumb = Size{Name: "thumb", MaxEdge: 320, Quality: 75}
+	Feed  = Size{Name: "feed", MaxEdge: 1080, Quality: 82}
+	Full  = Size{Name: "full", MaxEdge: 4096, Quality: 88}
+)
+
+// Sizes are the renditions made of every image.
+var Sizes = []Size{Thumb, Feed, Full}
+
+// Format is an encoding every size is written in.
+type Format struct {
+	ContentType string
+	Extension   string
+}
+
+var (
+	JPEG = Format{ContentType: "image/jpeg", Extension: "jpeg"}
+	WebP = Format{ContentType: "image/webp", Extension: "webp"}
+)
+
+// Formats are the encodings every rendition is written in.
+var Formats = []Format{WebP, JPEG}
+
+// Rendition is one encoded size of a processed image.
+type Rendition struct {
+	Size   Size
+	Format Format
+	Data   []byte
+}
+
+// Processed holds the renditions of an image along with the upright
+// dimensions of its Full rendition and a blurhash placeholder.
+type Processed struct {
+	Width      int
+	Height     int
+	BlurHash   string
+	Renditions []Rendition
+}
+
+// Rendition returns the rendition of the given size and format.
+func (processed *Processed) Rendition(size Size, format Format) (Rendition, bool) {
+	for _, rendition := range processed.Renditions {
+		if rendition.Size == size && rendition.Format == format {
+			return rendition, true
+		}
+	}
+	return Rendition{}, false
+}
+
+// Process decodes an uploaded image of any supported format, turns it upright
+// and re-encodes it at every size and format. Re-encoding drops all metadata,
+// so EXIF data such as GPS coordinates never reaches storage.
+func Process(r io.Reader) (*Processed, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxFileSize {
+		return nil, fmt.Errorf("%w: file is over %d bytes", ErrTooLarge, MaxFileSize)
+	}
+	img, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	processed := &Processed{}
+	for _, size := range Sizes {
+		resized := resize(img, size.MaxEdge)
+		if size == Full {
+			// The media URL points at the full rendition, so its
+			// dimensions are the ones clients lay out.
+			processed.Width, processed.Height = resized.Bounds().Dx(), resized.Bounds().Dy()
+		}
+		if size == Thumb {
+			// The smallest size is plenty for a 4x3 component hash.
+			hash, err := blurhash.Encode(4, 3, resized)
+			if err != nil {
+				return nil, fmt.Errorf("blurhash: %w", err)
+			}
+			processed.BlurHash = hash
+		}
+		for _, format := range Formats {
+			encoded, err := encode(resized, format, size.Quality)
+			if err != nil {
+				return nil, fmt.Errorf("encode %s %s: %w", size.Name, format.Extension, err)
+			}
+			processed.Renditions = append(processed.Renditions, Rendition{Size: size, Format: format, Data: encoded})
+		}
+	}
+	return processed, nil
+}
+
+// decode sniffs the real format of data rather than trusting the declared
+// content type, then decodes it upright.
+func decode(data []byte) (image.Image, error) {
+	contentType := Sniff(data)
+	var decodeConfig func(io.Reader) (image.Config, error)
+	var decodeImage func(io.Reader) (image.Image, error)
+	switch contentType {
+	case "image/jpeg":
+		decodeConfig, decodeImage = jpeg.DecodeConfig, jpeg.Decode
+	case "image/png":
+		decodeConfig, decodeImage = png.DecodeConfig, png.Decode
+	case "image/webp":
+		decodeConfig, decodeImage = webp.DecodeConfig, webp.Decode
+	case "image/heic":
+		decodeConfig, decodeImage = heic.DecodeConfig, heic.Decode
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, contentType)
+	}
+
+	// The header is enough to tell the dimensions, so oversized images are
+	// turned away before their pixels are allocated.
+	config, err := decodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, fmt.Errorf("%w: image is %dx%d", ErrUnsupported, config.Width, config.Height)
+	}
+	if int64(config.Width)*int64(config.Height) > maxPixels {
+		return nil, fmt.Errorf("%w: image is %dx%d, at most %d pixels allowed", ErrTooLarge, config.Width, config.Height, maxPixels)
+	}
+	img, err := decodeImage(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	// HEIC decoding already applies the container's rotation, and the other
+	// formats rarely carry EXIF, so only JPEGs need turning.
+	if contentType == "image/jpeg" {
+		img = orient(img, orientation(data))
+	}
+	return img, nil
+}
+
+// resize scales img down so its longest edge is at most maxEdge.
+func resize(img image.Image, maxEdge int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxEdge && height <= maxEdge {
+		return img
+	}
+	if width >= height {
+		height = max(1, height*maxEdge/width)
+		width = maxEdge
+	} else {
+		width = max(1, width*maxEdge/height)
+		height = maxEdge
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+func encode(img image.Image, format Format, quality int) ([]byte, error) {
+	var buffer bytes.Buffer
+	switch format {
+	case JPEG:
+		// JPEG has no alpha channel, so transparent areas are flattened
+		// onto white instead of turning black.
+		bounds := img.Bounds()
+		flat := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(flat, flat.Bounds(), img, bounds.Min, draw.Over)
+		if err := jpeg.Encode(&buffer, flat, &jpeg.Options{Quality: quality}); err != nil {
+			return nil, err
+		}
+	case WebP:
+		// Lossy like the JPEGs. The encoder is libwebp compiled to wasm, as
+		// the HEIC decoder is, so the build stays free of cgo.
+		if err := webp.Encode(&buffer, img, webp.Options{Quality: quality, Method: webp.DefaultMethod}); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown format %s", format.Extension)
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,298 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"testing"
+)
+
+var (
+	red  = color.RGBA{R: 255, A: 255}
+	blue = color.RGBA{B: 255, A: 255}
+)
+
+// halves returns an image whose top half is red and bottom half blue.
+func halves(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if y < height/2 {
+				img.Set(x, y, red)
+			} else {
+				img.Set(x, y, blue)
+			}
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+// encodeJPEG encodes img and, if orientation is set, adds an EXIF segment
+// carrying it.
+func encodeJPEG(t *testing.T, img image.Image, orientation uint16) []byte {
+	t.Helper()
+	var buffer bytes.Buffer
+	if err := jpeg.Encode(&buffer, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+	data := buffer.Bytes()
+	if orientation == 0 {
+		return data
+	}
+
+	// A little endian TIFF header and one IFD holding the orientation.
+	var tiff bytes.Buffer
+	tiff.WriteString("II*\x00")
+	binary.Write(&tiff, binary.LittleEndian, uint32(8))
+	binary.Write(&tiff, binary.LittleEndian, uint16(1))
+	binary.Write(&tiff, binary.LittleEndian, []uint16{0x0112, 3})
+	binary.Write(&tiff, binary.LittleEndian, uint32(1))
+	binary.Write(&tiff, binary.LittleEndian, []uint16{orientation, 0})
+	binary.Write(&tiff, binary.LittleEndian, uint32(0))
+
+	payload := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	withExif := append([]byte{}, data[:2]...)
+	withExif = append(withExif, segment...)
+	return append(withExif, data[2:]...)
+}
+
+func decodeRendition(t *testing.T, rendition Rendition) image.Image {
+	t.Helper()
+	img, _, err := image.Decode(bytes.NewReader(rendition.Data))
+	if err != nil {
+		t.Fatalf("%s rendition doesn't decode: %v", rendition.Size.Name, err)
+	}
+	return img
+}
+
+func near(c color.Color, want color.RGBA) bool {
+	r, g, b, _ := c.RGBA()
+	diff := func(got uint32, want uint8) bool {
+		delta := int(got>>8) - int(want)
+		return delta > -48 && delta < 48
+	}
+	return diff(r, want.R) && diff(g, want.G) && diff(b, want.B)
+}
+
+func TestProcessDimensions(t *testing.T) {
+	tests := []struct {
+		name       string
+		width      int
+		height     int
+		wantWidth  int
+		wantHeight int
+		wantSizes  map[string]image.Point
+	}{
+		{
+			name: "small image is not scaled up", width: 100, height: 50,
+			wantWidth: 100, wantHeight: 50,
+			wantSizes: map[string]image.Point{"thumb": {100, 50}, "feed": {100, 50}, "full": {100, 50}},
+		},
+		{
+			name: "wide image is capped at full size", width: 5000, height: 100,
+			wantWidth: 4096, wantHeight: 81,
+			wantSizes: map[string]image.Point{"thumb": {320, 6}, "feed": {1080, 21}, "full": {4096, 81}},
+		},
+		{
+			name: "tall image scales by its height", width: 400, height: 2000,
+			wantWidth: 400, wantHeight: 2000,
+			wantSizes: map[string]image.Point{"thumb": {64, 320}, "feed": {216, 1080}, "full": {400, 2000}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			processed, err := Process(bytes.NewReader(encodePNG(t, halves(test.width, test.height))))
+			if err != nil {
+				t.Fatalf("Process() = %v", err)
+			}
+			if processed.Width != test.wantWidth || processed.Height != test.wantHeight {
+				t.Errorf("Process() is %dx%d, want %dx%d", processed.Width, processed.Height, test.wantWidth, test.wantHeight)
+			}
+			if processed.BlurHash == "" {
+				t.Error("Process() has no blurhash")
+			}
+			if len(processed.Renditions) != len(Sizes)*len(Formats) {
+				t.Fatalf("Process() made %d renditions, want %d", len(processed.Renditions), len(Sizes)*len(Formats))
+			}
+			for _, rendition := range processed.Renditions {
+				if sniffed := Sniff(rendition.Data); sniffed != rendition.Format.ContentType {
+					t.Errorf("%s %s rendition sniffs as %s", rendition.Size.Name, rendition.Format.Extension, sniffed)
+				}
+				got := decodeRendition(t, rendition).Bounds().Size()
+				if want := test.wantSizes[rendition.Size.Name]; got != want {
+					t.Errorf("%s rendition is %v, want %v", rendition.Size.Name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessOrientsAndStripsExif(t *testing.T) {
+	tests := []struct {
+		name        string
+		orientation uint16
+		wantSize    image.Point
+		// Where the stored image's red top half ends up.
+		redAt, blueAt image.Point
+	}{
+		{name: "no exif", orientation: 0, wantSize: image.Pt(40, 20), redAt: image.Pt(20, 2), blueAt: image.Pt(20, 17)},
+		{name: "upright", orientation: 1, wantSize: image.Pt(40, 20), redAt: image.Pt(20, 2), blueAt: image.Pt(20, 17)},
+		{name: "rotated 180", orientation: 3, wantSize: image.Pt(40, 20), redAt: image.Pt(20, 17), blueAt: image.Pt(20, 2)},
+		{name: "rotated clockwise", orientation: 6, wantSize: image.Pt(20, 40), redAt: image.Pt(17, 20), blueAt: image.Pt(2, 20)},
+		{name: "rotated counter-clockwise", orientation: 8, wantSize: image.Pt(20, 40), redAt: image.Pt(2, 20), blueAt: image.Pt(17, 20)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := encodeJPEG(t, halves(40, 20), test.orientation)
+			if test.orientation != 0 && orientation(data) != int(test.orientation) {
+				t.Fatalf("test image has orientation %d, want %d", orientation(data), test.orientation)
+			}
+
+			processed, err := Process(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("Process() = %v", err)
+			}
+			if got := image.Pt(processed.Width, processed.Height); got != test.wantSize {
+				t.Errorf("Process() is %v, want %v", got, test.wantSize)
+			}
+			for _, rendition := range processed.Renditions {
+				if bytes.Contains(rendition.Data, []byte("Exif\x00\x00")) {
+					t.Errorf("%s rendition still has EXIF data", rendition.Size.Name)
+				}
+				if orientation(rendition.Data) != 1 {
+					t.Errorf("%s rendition has an orientation", rendition.Size.Name)
+				}
+				img := decodeRendition(t, rendition)
+				if !near(img.At(test.redAt.X, test.redAt.Y), red) || !near(img.At(test.blueAt.X, test.blueAt.Y), blue) {
+					t.Errorf("%s rendition isn't upright", rendition.Size.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// The stored top-left pixel is marked and followed to where each
+	// orientation puts it in a 3x2 image.
+	src := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	src.Set(0, 0, red)
+	tests := []struct {
+		orientation int
+		size        image.Point
+		marked      image.Point
+	}{
+		{orientation: 1, size: image.Pt(3, 2), marked: image.Pt(0, 0)},
+		{orientation: 2, size: image.Pt(3, 2), marked: image.Pt(2, 0)},
+		{orientation: 3, size: image.Pt(3, 2), marked: image.Pt(2, 1)},
+		{orientation: 4, size: image.Pt(3, 2), marked: image.Pt(0, 1)},
+		{orientation: 5, size: image.Pt(2, 3), marked: image.Pt(0, 0)},
+		{orientation: 6, size: image.Pt(2, 3), marked: image.Pt(1, 0)},
+		{orientation: 7, size: image.Pt(2, 3), marked: image.Pt(1, 2)},
+		{orientation: 8, size: image.Pt(2, 3), marked: image.Pt(0, 2)},
+		{orientation: 9, size: image.Pt(3, 2), marked: image.Pt(0, 0)},
+	}
+	for _, test := range tests {
+		img := orient(src, test.orientation)
+		if got := img.Bounds().Size(); got != test.size {
+			t.Errorf("orient(%d) is %v, want %v", test.orientation, got, test.size)
+			continue
+		}
+		if img.At(test.marked.X, test.marked.Y) != color.Color(red) {
+			t.Errorf("orient(%d) didn't move the top-left pixel to %v", test.orientation, test.marked)
+		}
+	}
+}
+
+// zeros reads as an endless run of zero bytes.
+type zeros struct{}
+
+func (zeros) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+// hugePNG is a 1x1 PNG whose header claims it is width by height.
+func hugePNG(t *testing.T, width, height uint32) []byte {
+	t.Helper()
+	data := encodePNG(t, image.NewRGBA(image.Rect(0, 0, 1, 1)))
+	// The IHDR chunk starts after the 8 byte signature: length, type,
+	// width, height, five more bytes of data, then its CRC.
+	binary.BigEndian.PutUint32(data[16:], width)
+	binary.BigEndian.PutUint32(data[20:], height)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+	return data
+}
+
+func TestProcessRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   func(t *testing.T) io.Reader
+		wantErr error
+	}{
+		{
+			name:    "not an image",
+			input:   func(*testing.T) io.Reader { return bytes.NewReader([]byte("hello, world")) },
+			wantErr: ErrUnsupported,
+		},
+		{
+			name:    "file over the size limit",
+			input:   func(*testing.T) io.Reader { return io.LimitReader(zeros{}, MaxFileSize+1) },
+			wantErr: ErrTooLarge,
+		},
+		{
+			name:    "too many pixels",
+			input:   func(t *testing.T) io.Reader { return bytes.NewReader(hugePNG(t, 10000, 10000)) },
+			wantErr: ErrTooLarge,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Process(test.input(t))
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("Process() = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestSniff(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []byte
+		want   string
+	}{
+		{name: "heic", header: []byte("\x00\x00\x00\x18ftypheic\x00\x00\x00\x00"), want: "image/heic"},
+		{name: "heif", header: []byte("\x00\x00\x00\x18ftypmif1\x00\x00\x00\x00"), want: "image/heic"},
+		{name: "quicktime", header: []byte("\x00\x00\x00\x14ftypqt  \x00\x00\x00\x00"), want: "video/quicktime"},
+		{name: "mp4", header: []byte("\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom"), want: "video/mp4"},
+		{name: "png", header: []byte("\x89PNG\r\n\x1a\n"), want: "image/png"},
+		{name: "jpeg", header: []byte("\xff\xd8\xff\xe0"), want: "image/jpeg"},
+		{name: "text", header: []byte("hello"), want: "text/plain"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Sniff(test.header); got != test.want {
+				t.Errorf("Sniff() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
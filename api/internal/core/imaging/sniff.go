@@ -0,0 +1,59 @@
+package imaging
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+// sniffLength is how much of a file is read to tell its content type.
+const sniffLength = 512
+
+// heifBrands are the ftyp brands of HEIC and HEIF stills.
+var heifBrands = [][]byte{
+	[]byte("heic"), []byte("heix"), []byte("heim"), []byte("heis"),
+	[]byte("mif1"), []byte("msf1"),
+}
+
+// Sniff tells a file's content type from its first bytes.
+// http.DetectContentType covers JPEG, PNG, WebP and MP4 but not the HEIC
+// photos and QuickTime videos iPhones produce, so their ftyp boxes are
+// checked first.
+func Sniff(header []byte) string {
+	if len(header) >= 12 && bytes.Equal(header[4:8], []byte("ftyp")) {
+		brand := header[8:12]
+		for _, heif := range heifBrands {
+			if bytes.Equal(brand, heif) {
+				return "image/heic"
+			}
+		}
+		if bytes.Equal(brand, []byte("qt  ")) {
+			return "video/quicktime"
+		}
+	}
+	contentType, _, _ := bytes.Cut([]byte(http.DetectContentType(header)), []byte(";"))
+	return string(contentType)
+}
+
+// SniffReader sniffs the content type of file and rewinds it.
+func SniffReader(file io.ReadSeeker) (string, error) {
+	contentType, err := SniffHeader(file)
+	if err != nil {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return contentType, nil
+}
+
+// SniffHeader sniffs the content type of a stream from its first bytes,
+// which it consumes.
+func SniffHeader(r io.Reader) (string, error) {
+	header := make([]byte, sniffLength)
+	n, err := io.ReadFull(r, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	return Sniff(header[:n]), nil
+}
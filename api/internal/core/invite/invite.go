@@ -0,0 +1,123 @@
+package invite
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/producer"
+	"api/internal/core/utils"
+	"context"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// codeAlphabet leaves out 0/O and 1/I so codes can be read out loud.
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+const codeLength = 8
+
+var (
+	ErrNotFound = errors.New("invite not found")
+	ErrUnusable = errors.New("invite is expired, revoked or used up")
+	ErrBlocked  = errors.New("user is blocked by a member of the group")
+)
+
+// NewCode returns a random invite code.
+func NewCode() (string, error) {
+	random := make([]byte, codeLength)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+	code := make([]byte, codeLength)
+	for i, b := range random {
+		// 256 is a multiple of the alphabet size, so this isn't biased.
+		code[i] = codeAlphabet[int(b)%len(codeAlphabet)]
+	}
+	return string(code), nil
+}
+
+// NormalizeCode lets users type codes in any case and with stray whitespace.
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Usable reports whether the invite can still be redeemed.
+func Usable(invite database.GroupInvite, now time.Time) bool {
+	if invite.Revoked {
+		return false
+	}
+	if invite.ExpiresAt.Valid && !invite.ExpiresAt.Time.After(now) {
+		return false
+	}
+	if invite.MaxUses != nil && invite.Uses >= *invite.MaxUses {
+		return false
+	}
+	return true
+}
+
+// Redeem adds the user to the invite's group as a member. Users who already
+// belong to the group get their membership back without using up the invite,
+// and only new members are announced to the group.
+func Redeem(ctx context.Context, queries *database.Queries, publisher *producer.Publisher, code string, userID uuid.UUID) (database.FriendGroupMember, error) {
+	invite, inviteErr := queries.GetGroupInvite(ctx, NormalizeCode(code))
+	if errors.Is(inviteErr, pgx.ErrNoRows) {
+		return database.FriendGroupMember{}, ErrNotFound
+	}
+	if inviteErr != nil {
+		return database.FriendGroupMember{}, inviteErr
+	}
+
+	getMember := database.GetGroupMemberParams{
+		GroupID: invite.GroupID,
+		UserID:  userID,
+	}
+	member, memberErr := queries.GetGroupMember(ctx, getMember)
+	if memberErr == nil {
+		return member, nil
+	}
+	if !errors.Is(memberErr, pgx.ErrNoRows) {
+		return database.FriendGroupMember{}, memberErr
+	}
+
+	checkBlocked := database.CheckBlockedInGroupParams{
+		UserID:  userID,
+		GroupID: invite.GroupID,
+	}
+	isBlocked, blockedErr := queries.CheckBlockedInGroup(ctx, checkBlocked)
+	if blockedErr != nil {
+		return database.FriendGroupMember{}, blockedErr
+	}
+	if isBlocked {
+		return database.FriendGroupMember{}, ErrBlocked
+	}
+
+	// The use is claimed in the same statement that checks the limits, so two
+	// users can't both take the last use. Adding the member happens in the
+	// same transaction so a failed add gives the use back.
+	txErr := queries.InTx(ctx, func(queries *database.Queries) error {
+		if _, redeemErr := queries.RedeemGroupInvite(ctx, invite.Code); redeemErr != nil {
+			if errors.Is(redeemErr, pgx.ErrNoRows) {
+				return ErrUnusable
+			}
+			return redeemErr
+		}
+
+		addUser := database.AddUserToGroupParams{
+			GroupID:  invite.GroupID,
+			UserID:   userID,
+			JoinedAt: utils.PGTime(),
+			Role:     database.GroupRoleMEMBER,
+		}
+		var addErr error
+		member, addErr = queries.AddUserToGroup(ctx, addUser)
+		return addErr
+	})
+	if txErr != nil {
+		return database.FriendGroupMember{}, txErr
+	}
+	publisher.MemberJoined(member)
+	return member, nil
+}
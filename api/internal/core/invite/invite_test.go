@@ -0,0 +1,93 @@
+package invite
+
+import (
+	database "api/internal/core/db"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestNewCode(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		code, err := NewCode()
+		if err != nil {
+			t.Fatalf("NewCode() = %v", err)
+		}
+		if len(code) != codeLength {
+			t.Fatalf("NewCode() = %q, want %d characters", code, codeLength)
+		}
+		for _, r := range code {
+			if !strings.ContainsRune(codeAlphabet, r) {
+				t.Fatalf("NewCode() = %q, which has %q outside the alphabet", code, r)
+			}
+		}
+		if NormalizeCode(code) != code {
+			t.Fatalf("NewCode() = %q, which isn't normalized", code)
+		}
+		if seen[code] {
+			t.Fatalf("NewCode() repeated %q", code)
+		}
+		seen[code] = true
+	}
+}
+
+func TestCodeAlphabetIsUnambiguous(t *testing.T) {
+	if strings.ContainsAny(codeAlphabet, "01IO") {
+		t.Errorf("alphabet %q has characters that are easy to misread", codeAlphabet)
+	}
+	if 256%len(codeAlphabet) != 0 {
+		t.Errorf("alphabet of %d characters makes codes biased", len(codeAlphabet))
+	}
+}
+
+func TestNormalizeCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{code: "ABCD2345", want: "ABCD2345"},
+		{code: "abcd2345", want: "ABCD2345"},
+		{code: "  aBcD2345\n", want: "ABCD2345"},
+		{code: "", want: ""},
+	}
+	for _, test := range tests {
+		if got := NormalizeCode(test.code); got != test.want {
+			t.Errorf("NormalizeCode(%q) = %q, want %q", test.code, got, test.want)
+		}
+	}
+}
+
+func TestUsable(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	limit := func(n int32) *int32 { return &n }
+	expires := func(at time.Time) pgtype.Timestamptz {
+		return pgtype.Timestamptz{Time: at, Valid: true}
+	}
+
+	tests := []struct {
+		name   string
+		invite database.GroupInvite
+		want   bool
+	}{
+		{name: "unlimited", invite: database.GroupInvite{Uses: 100}, want: true},
+		{name: "revoked", invite: database.GroupInvite{Revoked: true}, want: false},
+		{name: "expires later", invite: database.GroupInvite{ExpiresAt: expires(now.Add(time.Minute))}, want: true},
+		{name: "expires now", invite: database.GroupInvite{ExpiresAt: expires(now)}, want: false},
+		{name: "expired", invite: database.GroupInvite{ExpiresAt: expires(now.Add(-time.Minute))}, want: false},
+		{name: "uses left", invite: database.GroupInvite{MaxUses: limit(3), Uses: 2}, want: true},
+		{name: "used up", invite: database.GroupInvite{MaxUses: limit(3), Uses: 3}, want: false},
+		{name: "over used", invite: database.GroupInvite{MaxUses: limit(1), Uses: 2}, want: false},
+		{name: "single use unused", invite: database.GroupInvite{MaxUses: limit(1)}, want: true},
+		{name: "revoked with uses left", invite: database.GroupInvite{MaxUses: limit(3), Revoked: true}, want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Usable(test.invite, now); got != test.want {
+				t.Errorf("Usable() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
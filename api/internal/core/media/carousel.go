@@ -0,0 +1,123 @@
+package media
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/imaging"
+	"api/internal/core/storage"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// MaxCarouselItems caps how many images and videos a carousel post holds.
+const MaxCarouselItems = 10
+
+// CarouselUploader stores every "file" part of the request as one item of
+// the carousel, in the order the parts were sent.
+type CarouselUploader struct {
+	store storage.Storage
+}
+
+// carouselItem is a validated file waiting to be uploaded.
+type carouselItem struct {
+	header      *multipart.FileHeader
+	media       database.MediaType
+	contentType string
+}
+
+func (c CarouselUploader) upload(
+	queries *database.Queries,
+	post *database.Post,
+	ctx *gin.Context,
+) error {
+	fileHeaders, exists := ctx.Request.MultipartForm.File["file"]
+	if !exists || len(fileHeaders) == 0 {
+		return fmt.Errorf("carousel upload: no file provided")
+	}
+	if len(fileHeaders) > MaxCarouselItems {
+		return fmt.Errorf("carousel upload: %d files sent, at most %d allowed", len(fileHeaders), MaxCarouselItems)
+	}
+
+	// Validate every item before storing any of them.
+	items := make([]carouselItem, 0, len(fileHeaders))
+	for position, fileHeader := range fileHeaders {
+		item, err := validateCarouselItem(fileHeader)
+		if err != nil {
+			return fmt.Errorf("carousel upload: item %d: %w", position, err)
+		}
+		items = append(items, item)
+	}
+
+	// The post is marked FAILED if any item fails, so the files of the items
+	// stored before it are removed.
+	var stored []string
+	for position, item := range items {
+		keys, err := c.uploadItem(queries, post, item, int32(position))
+		if err != nil {
+			removeKeys(c.store, stored)
+			return fmt.Errorf("carousel upload: item %d: %w", position, err)
+		}
+		stored = append(stored, keys...)
+	}
+	return nil
+}
+
+// validateCarouselItem checks an item's real content type and its size
+// against the limit for images or videos.
+func validateCarouselItem(fileHeader *multipart.FileHeader) (carouselItem, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return carouselItem{}, fmt.Errorf("failed to open file: %v", err)
+	}
+	defer file.Close()
+
+	contentType, err := imaging.SniffReader(file)
+	if err != nil {
+		return carouselItem{}, fmt.Errorf("failed to read file: %v", err)
+	}
+	itemMedia, ok := ItemMedia(database.MediaTypeCAROUSEL, contentType)
+	if !ok {
+		return carouselItem{}, fmt.Errorf("unsupported content type %s", contentType)
+	}
+	if fileHeader.Size <= 0 || fileHeader.Size > maxUploadSize[itemMedia] {
+		return carouselItem{}, fmt.Errorf("file too large")
+	}
+	return carouselItem{header: fileHeader, media: itemMedia, contentType: contentType}, nil
+}
+
+// uploadItem stores one item and adds its row, returning the keys it
+// stored. Nothing is left in storage if it fails.
+func (c CarouselUploader) uploadItem(queries *database.Queries, post *database.Post, item carouselItem, position int32) ([]string, error) {
+	file, err := item.header.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %v", err)
+	}
+	defer file.Close()
+
+	id := uuid.New()
+	uploadContext, cancelUpload := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancelUpload()
+	if item.media == database.MediaTypeIMAGE {
+		_, keys, err := storeImage(uploadContext, queries, c.store, post, id, file, position)
+		return keys, err
+	}
+
+	key := mediaKey(item.media, id, uploadTypes[item.contentType].extension)
+	if err := c.store.Put(uploadContext, key, file, item.contentType); err != nil {
+		gin.DefaultWriter.Write([]byte("Failed to upload to storage: " + err.Error()))
+		return nil, err
+	}
+
+	createContext, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := createVideo(createContext, queries, post, id, c.store.URL(key), position); err != nil {
+		gin.DefaultWriter.Write([]byte("Failed to create carousel item: " + err.Error()))
+		removeKeys(c.store, []string{key})
+		return nil, err
+	}
+	return []string{key}, nil
+}
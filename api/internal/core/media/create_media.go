@@ -2,12 +2,14 @@ package media
 
 import (
 	database "api/internal/core/db"
+	"api/internal/core/producer"
+	"api/internal/core/storage"
 
 	"github.com/gin-gonic/gin"
 )
 
-func uploadMedia(queries *database.Queries, post *database.Post, ctx *gin.Context) error {
-	uploader := getUploader(post.Media)
+func uploadMedia(queries *database.Queries, store storage.Storage, post *database.Post, ctx *gin.Context) error {
+	uploader := getUploader(post.Media, store)
 	uploadErr := uploader.upload(queries, post, ctx)
 	if uploadErr != nil {
 		gin.DefaultWriter.Write([]byte("Failed to upload media: " + uploadErr.Error()))
@@ -16,27 +18,22 @@ func uploadMedia(queries *database.Queries, post *database.Post, ctx *gin.Contex
 	return nil
 }
 
-func CreateMedia(queries *database.Queries, post *database.Post, ctx *gin.Context) error {
+func CreateMedia(queries *database.Queries, store storage.Storage, publisher *producer.Publisher, post *database.Post, ctx *gin.Context) error {
 
 	postStatus := database.UpdatePostStatusParams{
 		ID: post.ID,
 	}
-	err := uploadMedia(queries, post, ctx)
+	err := uploadMedia(queries, store, post, ctx)
 	if err != nil {
 		gin.DefaultWriter.Write([]byte("Failed to upload media: " + err.Error()))
 		postStatus.Status = database.PostStatusFAILED
 		queries.UpdatePostStatus(ctx, postStatus)
 		return err
 	}
-	postStatus.Status = database.PostStatusPUBLISHED
-	queries.UpdatePostStatus(ctx, postStatus)
-
-	incrementErr := queries.IncrementPostCount(ctx.Request.Context(), post.UserID)
-	if incrementErr != nil {
-		gin.DefaultWriter.Write([]byte("Failed to increment post count: " + incrementErr.Error()))
-		postStatus.Status = database.PostStatusFAILED
-		queries.UpdatePostStatus(ctx, postStatus)
-		return incrementErr
+	publishErr := Publish(ctx, queries, publisher, post)
+	if publishErr != nil {
+		gin.DefaultWriter.Write([]byte("Failed to publish post: " + publishErr.Error()))
+		return publishErr
 	}
 	return nil
 }
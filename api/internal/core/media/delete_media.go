@@ -0,0 +1,104 @@
+package media
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/storage"
+	"context"
+	"errors"
+	"strings"
+)
+
+// ObjectKeys returns the bucket keys of a post's uploaded images and videos,
+// including every item of a carousel, every rendition of an image and every
+// upload that hasn't been completed yet. They have to be collected before the
+// post is deleted since the media and upload slot rows are removed along with
+// it.
+func ObjectKeys(ctx context.Context, queries *database.Queries, store storage.Storage, post database.Post) ([]string, error) {
+	var mediaURLs []string
+	switch post.Media {
+	case database.MediaTypeIMAGE:
+		images, err := queries.GetImages(ctx, post.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, image := range images {
+			mediaURLs = append(mediaURLs, image.MediaUrl)
+		}
+	case database.MediaTypeVIDEO:
+		videos, err := queries.GetVideos(ctx, post.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, video := range videos {
+			mediaURLs = append(mediaURLs, video.MediaUrl)
+		}
+	case database.MediaTypeCAROUSEL:
+		images, err := queries.GetImages(ctx, post.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, image := range images {
+			mediaURLs = append(mediaURLs, image.MediaUrl)
+		}
+		videos, err := queries.GetVideos(ctx, post.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, video := range videos {
+			mediaURLs = append(mediaURLs, video.MediaUrl)
+		}
+	}
+
+	slots, err := queries.ListUploadSlotsForPost(ctx, post.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(mediaURLs)+len(slots))
+	for _, mediaURL := range mediaURLs {
+		if key, ok := objectKey(store, mediaURL); ok {
+			keys = append(keys, renditionKeys(key)...)
+		}
+	}
+	for _, slot := range slots {
+		keys = append(keys, slot.ObjectKey)
+	}
+	return keys, nil
+}
+
+// AbortUploads aborts a post's resumable uploads that are still collecting
+// parts. Their slots go with the post, so this has to happen before it is
+// deleted or the upload janitor never finds the parts.
+func AbortUploads(ctx context.Context, queries *database.Queries, store storage.Storage, post database.Post) error {
+	multipart, ok := store.(storage.MultipartUploader)
+	if !ok {
+		return nil
+	}
+	slots, err := queries.ListUploadSlotsForPost(ctx, post.ID)
+	if err != nil {
+		return err
+	}
+	for _, slot := range slots {
+		if slot.UploadID == nil {
+			continue
+		}
+		err := multipart.AbortMultipartUpload(ctx, slot.ObjectKey, *slot.UploadID)
+		if err != nil && !errors.Is(err, storage.ErrNoSuchUpload) {
+			return err
+		}
+	}
+	return nil
+}
+
+// objectKey maps a media URL back to its key. Anything outside of the
+// images/ and videos/ prefixes was not uploaded by us and is left alone.
+func objectKey(store storage.Storage, mediaURL string) (string, bool) {
+	key, ok := store.Key(mediaURL)
+	if !ok {
+		return "", false
+	}
+	if !strings.HasPrefix(key, "images/") && !strings.HasPrefix(key, "videos/") {
+		return "", false
+	}
+	return key, true
+}
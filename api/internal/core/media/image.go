@@ -1,18 +1,18 @@
 package media
 
 import (
-	"api/internal/core/aws"
 	database "api/internal/core/db"
+	"api/internal/core/storage"
 	"context"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
-type ImageUploader struct {}
+type ImageUploader struct {
+	store storage.Storage
+}
 
 func (i ImageUploader) upload(
 	queries *database.Queries,
@@ -32,30 +32,14 @@ func (i ImageUploader) upload(
 	}
 	defer file.Close()
 
-	id := uuid.New()
-	filename := fmt.Sprintf("images/%s.jpeg", id.String())
-	mediaURL := fmt.Sprintf("https://%s/%s", os.Getenv("CLOUDFRONT_DOMAIN"), filename)
-
-	uploadErr := aws.ObjectUpload(filename, &file, "image/jpeg")
-
-	if uploadErr != nil {
-		gin.DefaultWriter.Write([]byte("Failed to upload to S3" + uploadErr.Error()))
-		return uploadErr
-	}
-
-	imageParams := database.CreateImageParams{
-		ID:       id,
-		PostID:   post.ID,
-		MediaUrl: mediaURL,
-	}
-
-	createContext, cancel := context.WithTimeout(context.Background(), 5 * time.Second)
-	defer cancel()
-	_, createErr := queries.CreateImage(createContext, imageParams)
-	if createErr != nil {
-		gin.DefaultWriter.Write([]byte("Failed to create image" + createErr.Error()))
-		cancel()
-		return createErr
+	// The image is decoded and re-encoded whatever its declared type, so
+	// renditions are always upright and carry no EXIF data.
+	uploadContext, cancelUpload := context.WithTimeout(context.Background(), 5 * time.Minute)
+	defer cancelUpload()
+	_, storeErr := StoreImage(uploadContext, queries, i.store, post, file, 0)
+	if storeErr != nil {
+		gin.DefaultWriter.Write([]byte("Failed to store image: " + storeErr.Error()))
+		return storeErr
 	}
 	return nil
 }
@@ -2,6 +2,7 @@ package media
 
 import (
 	database "api/internal/core/db"
+	"api/internal/core/storage"
 
 	"github.com/gin-gonic/gin"
 )
@@ -11,16 +12,18 @@ type Uploader interface {
 }
 
 // getUploader returns the uploader implementation for a given media type.
-func getUploader(media database.MediaType) Uploader {
+func getUploader(media database.MediaType, store storage.Storage) Uploader {
 	switch media {
 	case database.MediaTypeTEXT:
 		return TextUploader{}
 	case database.MediaTypeIMAGE:
-		return ImageUploader{}
+		return ImageUploader{store: store}
 	case database.MediaTypeLINK:
 		return LinkUploader{}
 	case database.MediaTypeVIDEO:
-		return VideoUploader{}
+		return VideoUploader{store: store}
+	case database.MediaTypeCAROUSEL:
+		return CarouselUploader{store: store}
 	// Add other cases here, e.g., "VIDEO": VideoUploader{}
 	default:
 		return nil
@@ -0,0 +1,38 @@
+package media
+
+import "time"
+
+const (
+	// minPartSize is the smallest part S3 accepts, except for the last one.
+	minPartSize int64 = 5 << 20
+	// defaultPartSize keeps a retried part cheap on a phone connection.
+	defaultPartSize int64 = 8 << 20
+	maxParts        int64 = 10000
+
+	// ResumableUploadExpiry is how long a resumable upload may sit idle
+	// before it counts as abandoned. Asking for part URLs resets it.
+	ResumableUploadExpiry = 24 * time.Hour
+)
+
+// PartSize picks the part size for a resumable upload of size bytes.
+func PartSize(size int64) int64 {
+	partSize := defaultPartSize
+	if needed := (size + maxParts - 1) / maxParts; needed > partSize {
+		partSize = needed
+	}
+	return max(partSize, minPartSize)
+}
+
+// PartCount is how many parts an upload of size bytes is split into.
+func PartCount(size int64, partSize int64) int32 {
+	return int32((size + partSize - 1) / partSize)
+}
+
+// ExpectedPartSize is the size part number must have. Every part is
+// partSize long except the last, which holds the remainder.
+func ExpectedPartSize(size int64, partSize int64, number int32) int64 {
+	if number < PartCount(size, partSize) {
+		return partSize
+	}
+	return size - partSize*int64(number-1)
+}
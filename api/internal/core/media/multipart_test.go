@@ -0,0 +1,72 @@
+package media
+
+import "testing"
+
+func TestPartCount(t *testing.T) {
+	const part = 8 << 20
+	tests := []struct {
+		name string
+		size int64
+		want int32
+	}{
+		{name: "one byte", size: 1, want: 1},
+		{name: "exactly one part", size: part, want: 1},
+		{name: "one byte over", size: part + 1, want: 2},
+		{name: "exact multiple", size: 3 * part, want: 3},
+		{name: "remainder", size: 3*part + 42, want: 4},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := PartCount(test.size, part); got != test.want {
+				t.Errorf("PartCount(%d) = %d, want %d", test.size, got, test.want)
+			}
+		})
+	}
+}
+
+func TestExpectedPartSize(t *testing.T) {
+	const part = 8 << 20
+	tests := []struct {
+		name   string
+		size   int64
+		number int32
+		want   int64
+	}{
+		{name: "single short part", size: 100, number: 1, want: 100},
+		{name: "single full part", size: part, number: 1, want: part},
+		{name: "first of several", size: 2*part + 7, number: 1, want: part},
+		{name: "middle part", size: 2*part + 7, number: 2, want: part},
+		{name: "last part holds remainder", size: 2*part + 7, number: 3, want: 7},
+		{name: "last part of exact multiple", size: 2 * part, number: 2, want: part},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ExpectedPartSize(test.size, part, test.number); got != test.want {
+				t.Errorf("ExpectedPartSize(%d, %d) = %d, want %d", test.size, test.number, got, test.want)
+			}
+		})
+	}
+}
+
+func TestPartSize(t *testing.T) {
+	tests := []struct {
+		name string
+		size int64
+		want int64
+	}{
+		{name: "small upload", size: 1 << 20, want: defaultPartSize},
+		{name: "default fits max parts", size: defaultPartSize * maxParts, want: defaultPartSize},
+		{name: "grows past max parts", size: defaultPartSize*maxParts + maxParts, want: defaultPartSize + 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := PartSize(test.size)
+			if got != test.want {
+				t.Errorf("PartSize(%d) = %d, want %d", test.size, got, test.want)
+			}
+			if int64(PartCount(test.size, got)) > maxParts {
+				t.Errorf("PartSize(%d) needs %d parts, more than %d", test.size, PartCount(test.size, got), maxParts)
+			}
+		})
+	}
+}
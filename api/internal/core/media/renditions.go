@@ -0,0 +1,113 @@
+package media
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/imaging"
+	"api/internal/core/storage"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// renditionKeyPattern matches the key of a processed image's full-size JPEG,
+// which is what its media URL points at.
+var renditionKeyPattern = regexp.MustCompile(`^images/([0-9a-f-]{36})/full\.jpeg$`)
+
+// renditionKey is where one rendition of an image is stored. Every rendition
+// of an image shares the images/<id>/ prefix, so clients can swap the file
+// name of the media URL, e.g. full.jpeg for thumb.webp.
+func renditionKey(id uuid.UUID, size imaging.Size, format imaging.Format) string {
+	return fmt.Sprintf("images/%s/%s.%s", id.String(), size.Name, format.Extension)
+}
+
+// renditionKeys returns the keys of every rendition of the image whose
+// full-size key is fullKey, or just fullKey for images stored before
+// processing was added.
+func renditionKeys(fullKey string) []string {
+	match := renditionKeyPattern.FindStringSubmatch(fullKey)
+	if match == nil {
+		return []string{fullKey}
+	}
+	id, err := uuid.Parse(match[1])
+	if err != nil {
+		return []string{fullKey}
+	}
+	keys := make([]string, 0, len(imaging.Sizes)*len(imaging.Formats))
+	for _, size := range imaging.Sizes {
+		for _, format := range imaging.Formats {
+			keys = append(keys, renditionKey(id, size, format))
+		}
+	}
+	return keys
+}
+
+// StoreImage processes an uploaded image into a new images row of post.
+func StoreImage(ctx context.Context, queries *database.Queries, store storage.Storage, post *database.Post, file io.Reader, position int32) (database.Image, error) {
+	image, _, err := storeImage(ctx, queries, store, post, uuid.New(), file, position)
+	return image, err
+}
+
+// storeImage processes an uploaded image, stores its renditions and adds its
+// images row pointing at the full-size JPEG. It returns the keys it stored;
+// if anything fails they are deleted again before returning.
+func storeImage(ctx context.Context, queries *database.Queries, store storage.Storage, post *database.Post, id uuid.UUID, file io.Reader, position int32) (database.Image, []string, error) {
+	createImage, keys, err := putImage(ctx, store, post, id, file, position)
+	if err != nil {
+		return database.Image{}, nil, err
+	}
+	image, err := queries.CreateImage(ctx, createImage)
+	if err != nil {
+		removeKeys(store, keys)
+		return database.Image{}, nil, err
+	}
+	return image, keys, nil
+}
+
+// putImage processes an uploaded image and stores its renditions, returning
+// the images row to add for it and the keys it stored. If anything fails the
+// stored keys are deleted again before returning.
+func putImage(ctx context.Context, store storage.Storage, post *database.Post, id uuid.UUID, file io.Reader, position int32) (database.CreateImageParams, []string, error) {
+	processed, err := imaging.Process(file)
+	if err != nil {
+		return database.CreateImageParams{}, nil, fmt.Errorf("failed to process image: %w", err)
+	}
+
+	keys := make([]string, 0, len(processed.Renditions))
+	for _, rendition := range processed.Renditions {
+		key := renditionKey(id, rendition.Size, rendition.Format)
+		if err := store.Put(ctx, key, bytes.NewReader(rendition.Data), rendition.Format.ContentType); err != nil {
+			removeKeys(store, keys)
+			return database.CreateImageParams{}, nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	blurhash := processed.BlurHash
+	return database.CreateImageParams{
+		ID:       id,
+		PostID:   post.ID,
+		MediaUrl: store.URL(renditionKey(id, imaging.Full, imaging.JPEG)),
+		Position: position,
+		Width:    int32(processed.Width),
+		Height:   int32(processed.Height),
+		Blurhash: &blurhash,
+	}, keys, nil
+}
+
+// removeKeys deletes stored files that no row refers to.
+func removeKeys(store storage.Storage, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	deleteContext, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := store.Delete(deleteContext, keys); err != nil {
+		log.Printf("Failed to remove %d stored files: %v", len(keys), err)
+	}
+}
@@ -0,0 +1,269 @@
+package media
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/imaging"
+	"api/internal/core/producer"
+	"api/internal/core/storage"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+// MaxUploadSlots caps how many files a single post can upload directly. It
+// matches MaxCarouselItems so a carousel can be uploaded either way.
+const MaxUploadSlots = MaxCarouselItems
+
+var (
+	// ErrNotPending is returned when uploads are added to a post that has
+	// already been published.
+	ErrNotPending = errors.New("post is not pending")
+	// ErrTooManyUploads is returned when uploads would take a post past
+	// MaxUploadSlots.
+	ErrTooManyUploads = errors.New("too many uploads for post")
+)
+
+// uploadType is what a content type uploads as: an image or a video, and the
+// extension its key gets.
+type uploadType struct {
+	media     database.MediaType
+	extension string
+}
+
+// uploadTypes lists the content types accepted for uploads.
+var uploadTypes = map[string]uploadType{
+	"image/jpeg":      {media: database.MediaTypeIMAGE, extension: "jpeg"},
+	"image/png":       {media: database.MediaTypeIMAGE, extension: "png"},
+	"image/heic":      {media: database.MediaTypeIMAGE, extension: "heic"},
+	"image/webp":      {media: database.MediaTypeIMAGE, extension: "webp"},
+	"video/mp4":       {media: database.MediaTypeVIDEO, extension: "mp4"},
+	"video/quicktime": {media: database.MediaTypeVIDEO, extension: "mov"},
+}
+
+var maxUploadSize = map[database.MediaType]int64{
+	database.MediaTypeIMAGE: imaging.MaxFileSize,
+	database.MediaTypeVIDEO: 500 << 20, // 500 MB
+}
+
+// TakesUploads reports whether posts of this media type are made of uploaded
+// files.
+func TakesUploads(media database.MediaType) bool {
+	return media == database.MediaTypeIMAGE || media == database.MediaTypeVIDEO || media == database.MediaTypeCAROUSEL
+}
+
+// ItemMedia returns whether a file of contentType is stored as an image or a
+// video, or false if posts of this media type don't accept it. Carousel posts
+// accept both.
+func ItemMedia(media database.MediaType, contentType string) (database.MediaType, bool) {
+	upload, ok := uploadTypes[contentType]
+	if !ok {
+		return "", false
+	}
+	if media != upload.media && media != database.MediaTypeCAROUSEL {
+		return "", false
+	}
+	return upload.media, true
+}
+
+// UploadKey returns the key a direct upload is stored under, or false if the
+// post's media type doesn't accept contentType.
+func UploadKey(media database.MediaType, id uuid.UUID, contentType string) (string, bool) {
+	itemMedia, ok := ItemMedia(media, contentType)
+	if !ok {
+		return "", false
+	}
+	return mediaKey(itemMedia, id, uploadTypes[contentType].extension), true
+}
+
+// MaxUploadSize is the largest file of contentType a post of this media type
+// may upload, or 0 if it doesn't accept that content type at all.
+func MaxUploadSize(media database.MediaType, contentType string) int64 {
+	itemMedia, ok := ItemMedia(media, contentType)
+	if !ok {
+		return 0
+	}
+	return maxUploadSize[itemMedia]
+}
+
+// NextPosition is the position the next item uploaded to a post goes in,
+// after every slot it already has.
+func NextPosition(slots []database.UploadSlot) int32 {
+	var position int32
+	for _, slot := range slots {
+		if slot.Position >= position {
+			position = slot.Position + 1
+		}
+	}
+	return position
+}
+
+// CreateUploadSlots adds slots to a PENDING post, placing them after every
+// slot it already has in the order given. The post row stays locked while the
+// slots are counted and inserted, so concurrent requests can't take the post
+// past MaxUploadSlots or hand out the same position twice.
+func CreateUploadSlots(ctx context.Context, queries *database.Queries, postID uuid.UUID, slots []database.CreateUploadSlotParams) ([]database.UploadSlot, error) {
+	created := make([]database.UploadSlot, 0, len(slots))
+	txErr := queries.InTx(ctx, func(queries *database.Queries) error {
+		status, err := queries.LockPost(ctx, postID)
+		if err != nil {
+			return err
+		}
+		if status != database.PostStatusPENDING {
+			return ErrNotPending
+		}
+		existing, err := queries.ListUploadSlotsForPost(ctx, postID)
+		if err != nil {
+			return err
+		}
+		if len(existing)+len(slots) > MaxUploadSlots {
+			return ErrTooManyUploads
+		}
+
+		position := NextPosition(existing)
+		for i, slot := range slots {
+			slot.PostID = postID
+			slot.Position = position + int32(i)
+			uploadSlot, err := queries.CreateUploadSlot(ctx, slot)
+			if err != nil {
+				return err
+			}
+			created = append(created, uploadSlot)
+		}
+		return nil
+	})
+	if txErr != nil {
+		return nil, txErr
+	}
+	return created, nil
+}
+
+// SniffUpload tells the content type of a stored upload from its first bytes,
+// so a client can't pass off one kind of file as another by declaring it.
+func SniffUpload(ctx context.Context, store storage.Storage, key string) (string, error) {
+	object, err := store.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer object.Close()
+	return imaging.SniffHeader(object)
+}
+
+// CreateUploadedMedia adds the images and videos rows for a post's completed
+// uploads in one transaction, so the post gets all of its items or none.
+// Slot IDs become media IDs and the slots' positions are kept. Images are
+// processed into renditions before the transaction starts, and their
+// originals, which may still carry EXIF data, are deleted afterwards. If
+// anything fails every uploaded and processed file is deleted.
+func CreateUploadedMedia(ctx context.Context, queries *database.Queries, store storage.Storage, post *database.Post, slots []database.UploadSlot) error {
+	var images []database.CreateImageParams
+	var videos []database.CreateVideoParams
+	var stored, originals []string
+	fail := func(err error) error {
+		for _, slot := range slots {
+			stored = append(stored, slot.ObjectKey)
+		}
+		removeKeys(store, stored)
+		return err
+	}
+
+	for _, slot := range slots {
+		itemMedia, ok := ItemMedia(post.Media, slot.ContentType)
+		if !ok {
+			return fail(fmt.Errorf("%s posts don't take %s uploads", post.Media, slot.ContentType))
+		}
+		if itemMedia == database.MediaTypeVIDEO {
+			videos = append(videos, database.CreateVideoParams{
+				ID:       slot.ID,
+				PostID:   post.ID,
+				MediaUrl: store.URL(slot.ObjectKey),
+				Position: slot.Position,
+			})
+			continue
+		}
+
+		original, err := store.Get(ctx, slot.ObjectKey)
+		if err != nil {
+			return fail(err)
+		}
+		image, keys, err := putImage(ctx, store, post, slot.ID, original, slot.Position)
+		original.Close()
+		if err != nil {
+			return fail(err)
+		}
+		images = append(images, image)
+		stored = append(stored, keys...)
+		originals = append(originals, slot.ObjectKey)
+	}
+
+	txErr := queries.InTx(ctx, func(queries *database.Queries) error {
+		for _, image := range images {
+			if _, err := queries.CreateImage(ctx, image); err != nil {
+				return err
+			}
+		}
+		for _, video := range videos {
+			if _, err := queries.CreateVideo(ctx, video); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if txErr != nil {
+		return fail(txErr)
+	}
+	if len(originals) > 0 {
+		if err := store.Delete(ctx, originals); err != nil {
+			log.Printf("Failed to delete %d original uploads: %v", len(originals), err)
+		}
+	}
+	return nil
+}
+
+// createVideo adds the videos row of one uploaded video.
+func createVideo(ctx context.Context, queries *database.Queries, post *database.Post, id uuid.UUID, mediaURL string, position int32) error {
+	_, err := queries.CreateVideo(ctx, database.CreateVideoParams{
+		ID:       id,
+		PostID:   post.ID,
+		MediaUrl: mediaURL,
+		Position: position,
+	})
+	return err
+}
+
+// mediaKey is the storage key of an uploaded image or video.
+func mediaKey(itemMedia database.MediaType, id uuid.UUID, extension string) string {
+	if itemMedia == database.MediaTypeVIDEO {
+		return fmt.Sprintf("videos/%s.%s", id.String(), extension)
+	}
+	return fmt.Sprintf("images/%s.%s", id.String(), extension)
+}
+
+// Publish marks a post whose media is in place as PUBLISHED, counts it for
+// its author and announces it to its groups.
+func Publish(ctx context.Context, queries *database.Queries, publisher *producer.Publisher, post *database.Post) error {
+	postStatus := database.UpdatePostStatusParams{
+		ID:     post.ID,
+		Status: database.PostStatusPUBLISHED,
+	}
+	if err := queries.UpdatePostStatus(ctx, postStatus); err != nil {
+		return err
+	}
+	if err := queries.IncrementPostCount(ctx, post.UserID); err != nil {
+		postStatus.Status = database.PostStatusFAILED
+		queries.UpdatePostStatus(ctx, postStatus)
+		return err
+	}
+	post.Status = database.PostStatusPUBLISHED
+
+	groupIDs, err := queries.ListGroupsForPost(ctx, post.ID)
+	if err != nil {
+		// The post is live either way; only the realtime event is lost.
+		log.Printf("Failed to fetch groups of post %s: %v", post.ID, err)
+		return nil
+	}
+	publisher.PostPublished(*post, groupIDs)
+	return nil
+}
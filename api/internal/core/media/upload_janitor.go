@@ -0,0 +1,114 @@
+package media
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/storage"
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// UploadJanitor cleans up uploads the client walked away from. Their objects
+// and unfinished multipart uploads are removed and the PENDING post they
+// belonged to is marked FAILED.
+type UploadJanitor struct {
+	queries *database.Queries
+	store   storage.Storage
+	// grace is how long past its expiry a slot is kept, so a client that
+	// finished uploading just in time can still complete it.
+	grace time.Duration
+}
+
+// janitorBatch bounds how many slots a single pass handles.
+const janitorBatch = 100
+
+func NewUploadJanitor(queries *database.Queries, store storage.Storage, grace time.Duration) *UploadJanitor {
+	return &UploadJanitor{
+		queries: queries,
+		store:   store,
+		grace:   grace,
+	}
+}
+
+// Start cleans up on every interval until ctx is cancelled. It returns
+// immediately.
+func (janitor *UploadJanitor) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				janitor.clean(ctx)
+			}
+		}
+	}()
+}
+
+func (janitor *UploadJanitor) clean(ctx context.Context) {
+	listContext, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	expired := database.ListExpiredUploadSlotsParams{
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(-janitor.grace), Valid: true},
+		Limit:     janitorBatch,
+	}
+	slots, err := janitor.queries.ListExpiredUploadSlots(listContext, expired)
+	if err != nil {
+		log.Printf("Failed to list abandoned uploads: %v", err)
+		return
+	}
+	for _, slot := range slots {
+		janitor.remove(ctx, slot)
+	}
+}
+
+func (janitor *UploadJanitor) remove(ctx context.Context, slot database.UploadSlot) {
+	removeContext, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var err error
+	if multipart, ok := janitor.store.(storage.MultipartUploader); ok && slot.UploadID != nil {
+		err = multipart.AbortMultipartUpload(removeContext, slot.ObjectKey, *slot.UploadID)
+		if errors.Is(err, storage.ErrNoSuchUpload) {
+			err = nil
+		}
+	}
+	// An assembled or directly uploaded object is left over as well.
+	if err == nil {
+		err = janitor.store.Delete(removeContext, []string{slot.ObjectKey})
+	}
+	if err != nil {
+		// Keep the slot so the next pass tries again.
+		log.Printf("Failed to remove abandoned upload %s: %v", slot.ID, err)
+		return
+	}
+	if err := janitor.queries.DeleteUploadSlot(removeContext, slot.ID); err != nil {
+		log.Printf("Failed to delete upload slot %s: %v", slot.ID, err)
+		return
+	}
+
+	post, err := janitor.queries.GetPost(removeContext, slot.PostID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to fetch post %s of abandoned upload: %v", slot.PostID, err)
+		return
+	}
+	if post.Status != database.PostStatusPENDING {
+		return
+	}
+	postStatus := database.UpdatePostStatusParams{
+		ID:     post.ID,
+		Status: database.PostStatusFAILED,
+	}
+	if err := janitor.queries.UpdatePostStatus(removeContext, postStatus); err != nil {
+		log.Printf("Failed to fail post %s of abandoned upload: %v", post.ID, err)
+	}
+}
@@ -1,18 +1,20 @@
 package media
 
 import (
-	"api/internal/core/aws"
 	database "api/internal/core/db"
+	"api/internal/core/imaging"
+	"api/internal/core/storage"
 	"context"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
-type VideoUploader struct {}
+type VideoUploader struct {
+	store storage.Storage
+}
 
 func (v VideoUploader) upload(
 	queries *database.Queries,
@@ -32,14 +34,25 @@ func (v VideoUploader) upload(
 	}
 	defer file.Close()
 
+	// The key's extension and the stored content type come from the file
+	// itself, so QuickTime uploads aren't served as MP4.
+	contentType, err := imaging.SniffReader(file)
+	if err != nil {
+		return fmt.Errorf("video upload: failed to read file: %v", err)
+	}
 	id := uuid.New()
-	filename := fmt.Sprintf("videos/%s.mp4", id.String())
-	mediaURL := fmt.Sprintf("https://%s/%s", os.Getenv("CLOUDFRONT_DOMAIN"), filename)
+	filename, ok := UploadKey(database.MediaTypeVIDEO, id, contentType)
+	if !ok {
+		return fmt.Errorf("video upload: unsupported content type %s", contentType)
+	}
+	mediaURL := v.store.URL(filename)
 
-	uploadErr := aws.ObjectUpload(filename, &file, "video/mp4")
+	uploadContext, cancelUpload := context.WithTimeout(context.Background(), 5 * time.Minute)
+	defer cancelUpload()
+	uploadErr := v.store.Put(uploadContext, filename, file, contentType)
 
 	if uploadErr != nil {
-		gin.DefaultWriter.Write([]byte("Failed to upload to S3" + uploadErr.Error()))
+		gin.DefaultWriter.Write([]byte("Failed to upload to storage: " + uploadErr.Error()))
 		return uploadErr
 	}
 
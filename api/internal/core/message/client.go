@@ -1,64 +1,298 @@
 package message
 
 import (
+	database "api/internal/core/db"
+	"context"
+	"encoding/json"
 	"log"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
 
+const (
+	// Time allowed to write a message to the peer.
+	writeWait = 10 * time.Second
+
+	// Time allowed to read the next pong message from the peer. Connections
+	// that miss it are treated as dead.
+	pongWait = 60 * time.Second
+
+	// Send pings to peer with this period. Must be less than pongWait.
+	pingPeriod = (pongWait * 9) / 10
+
+	// Clients only send small commands.
+	maxMessageSize = 4096
+)
+
 // Client represents a single WebSocket connection.
 // The ID field uniquely identifies the end-user connection.
 type Client struct {
-	hub    *Hub
-	conn   *websocket.Conn
-	send   chan []byte
-	ID     uuid.UUID // Unique identifier for the WS client (end-user)
-	Groups []uuid.UUID
+	hub  *Hub
+	conn *websocket.Conn
+	send chan []byte
+	ID   uuid.UUID // Unique identifier for the WS client (end-user)
+	// groupOnly clients follow their groups but not events targeted at the
+	// user. SSE streams are one group feed.
+	groupOnly bool
+
+	// done is closed once the connection is finished with. Nothing closes
+	// send, so writers select on done instead.
+	done      chan struct{}
+	closeOnce sync.Once
+
+	// mutex guards the subscriptions and replay state, which the hub reads
+	// while delivering.
+	mutex  sync.Mutex
+	groups map[uuid.UUID]bool
+	// replaying holds back live messages while missed events are replayed.
+	replaying bool
+	held      []WSMessage
+	overflow  bool
+	// presence is the state last relayed for each group.
+	presence map[uuid.UUID]presenceSent
+}
+
+// clientCommand is a message sent by the client. It is one of
+//
+//	{"type":"subscribe","group":"<groupId>"}
+//	{"type":"unsubscribe","group":"<groupId>"}
+//	{"type":"resume","sequences":{"<groupId>":41}}
+//	{"type":"presence","group":"<groupId>","state":"viewing"}
+type clientCommand struct {
+	Type      string              `json:"type"`
+	Group     uuid.UUID           `json:"group"`
+	Sequences map[uuid.UUID]int64 `json:"sequences"`
+	State     PresenceState       `json:"state"`
 }
 
-// readPump reads messages from the WebSocket connection.
-// For notifications, this might only be used for pings or minimal commands.
+// statusMessage answers a client command.
+type statusMessage struct {
+	Type     string    `json:"type"`
+	Group    uuid.UUID `json:"group"`
+	Sequence int64     `json:"sequence,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// readPump reads commands from the WebSocket connection and keeps the read
+// deadline moving while pongs arrive.
 func (client *Client) readPump() {
 	defer func() {
-		client.hub.unregister <- client
-		client.conn.Close()
+		client.hub.unregister(client)
+		client.close()
+		client.clearPresence()
 	}()
 
+	client.conn.SetReadLimit(maxMessageSize)
+	client.conn.SetReadDeadline(time.Now().Add(pongWait))
+	client.conn.SetPongHandler(func(string) error {
+		return client.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
 	for {
 		_, message, err := client.conn.ReadMessage()
 		if err != nil {
-			log.Println("Read error:", err)
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				log.Println("Read error:", err)
+			}
 			break
 		}
-		log.Printf("Received message from client %s: %s", client.ID, message)
+
+		var command clientCommand
+		if err := json.Unmarshal(message, &command); err != nil {
+			log.Printf("Received message from client %s: %s", client.ID, message)
+			continue
+		}
+		switch command.Type {
+		case "subscribe":
+			client.handleSubscribe(command.Group)
+		case "unsubscribe":
+			client.hub.unsubscribe(client, command.Group)
+			client.sendStatus(statusMessage{Type: "unsubscribed", Group: command.Group})
+		case "presence":
+			client.handlePresence(command.Group, command.State)
+		case "resume":
+			if !client.resume(command.Sequences) {
+				return
+			}
+		default:
+			log.Printf("Received message from client %s: %s", client.ID, message)
+		}
 	}
 }
 
-// writePump writes messages from the hub to the WebSocket connection.
+// writePump writes messages from the hub to the WebSocket connection and
+// pings the peer so dead connections are noticed.
 func (client *Client) writePump() {
-	defer client.conn.Close()
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		client.close()
+	}()
 
 	for {
 		select {
-		case msg, ok := <-client.send:
-			if !ok {
-				log.Println("Hub closed the channel for client", client.ID)
-				return
-			}
+		case msg := <-client.send:
+			client.conn.SetWriteDeadline(time.Now().Add(writeWait))
 			err := client.conn.WriteMessage(websocket.TextMessage, msg)
 			if err != nil {
 				log.Println("Write error for client", client.ID, ":", err)
 				return
 			}
+		case <-ticker.C:
+			client.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := client.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-client.done:
+			client.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			client.conn.WriteMessage(websocket.CloseMessage, []byte{})
+			return
+		}
+	}
+}
+
+// close stops both pumps, or the SSE stream. It is safe to call from the hub
+// and either pump.
+func (client *Client) close() {
+	client.closeOnce.Do(func() {
+		close(client.done)
+		if client.conn != nil {
+			client.conn.Close()
+		}
+	})
+}
+
+// deliver queues a message for the client, or holds it back while a replay is
+// running. It returns false if the client is too slow to keep up.
+func (client *Client) deliver(msg WSMessage) bool {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	if client.replaying {
+		if len(client.held) >= cap(client.send) {
+			client.overflow = true
+			return true
+		}
+		client.held = append(client.held, msg)
+		return true
+	}
+
+	select {
+	case <-client.done:
+		return true
+	case client.send <- msg.Data:
+		return true
+	default:
+		return false
+	}
+}
+
+// handleSubscribe adds a group to the client's subscriptions once the user is
+// confirmed to be a member.
+func (client *Client) handleSubscribe(groupID uuid.UUID) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	checkMembership := database.CheckUserMembershipParams{
+		GroupID: groupID,
+		UserID:  client.ID,
+	}
+	isMember, err := client.hub.queries.CheckUserMembership(ctx, checkMembership)
+	if err != nil {
+		log.Printf("Failed to check membership of %s in group %s: %v", client.ID, groupID, err)
+		client.sendStatus(statusMessage{Type: "error", Group: groupID, Error: "Failed to check membership"})
+		return
+	}
+	if !isMember {
+		client.sendStatus(statusMessage{Type: "error", Group: groupID, Error: "Unauthorized"})
+		return
+	}
+	client.hub.subscribe(client, groupID)
+
+	// A revocation handled between the check above and subscribing found
+	// nothing to remove. Membership is removed before the revocation is
+	// published, so checking again afterwards catches it.
+	isMember, err = client.hub.queries.CheckUserMembership(ctx, checkMembership)
+	if err != nil || !isMember {
+		client.hub.unsubscribe(client, groupID)
+		if err != nil {
+			log.Printf("Failed to check membership of %s in group %s: %v", client.ID, groupID, err)
+			client.sendStatus(statusMessage{Type: "error", Group: groupID, Error: "Failed to check membership"})
+			return
 		}
+		client.sendStatus(statusMessage{Type: "error", Group: groupID, Error: "Unauthorized"})
+		return
+	}
+	client.sendStatus(statusMessage{Type: "subscribed", Group: groupID})
+}
+
+func (client *Client) subscriptions() []uuid.UUID {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	groups := make([]uuid.UUID, 0, len(client.groups))
+	for groupID := range client.groups {
+		groups = append(groups, groupID)
+	}
+	return groups
+}
+
+func (client *Client) subscribed(groupID uuid.UUID) bool {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	return client.groups[groupID]
+}
+
+// addSubscription returns false if the client was already subscribed.
+func (client *Client) addSubscription(groupID uuid.UUID) bool {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	if client.groups[groupID] {
+		return false
+	}
+	client.groups[groupID] = true
+	return true
+}
+
+// removeSubscription returns false if the client wasn't subscribed.
+func (client *Client) removeSubscription(groupID uuid.UUID) bool {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	if !client.groups[groupID] {
+		return false
+	}
+	delete(client.groups, groupID)
+	return true
+}
+
+// sendStatus writes a reply from the read pump. It waits for room in the send
+// buffer since replies are rare and must not be dropped silently.
+func (client *Client) sendStatus(status statusMessage) bool {
+	payload, err := json.Marshal(status)
+	if err != nil {
+		log.Printf("Failed to encode %s for client %s: %v", status.Type, client.ID, err)
+		return true
+	}
+	return client.sendBlocking(payload)
+}
+
+// sendBlocking waits up to writeWait for room in the send buffer.
+func (client *Client) sendBlocking(payload []byte) bool {
+	select {
+	case client.send <- payload:
+		return true
+	case <-client.done:
+		return false
+	case <-time.After(writeWait):
+		return false
 	}
 }
 
 // ServeWS upgrades the HTTP connection to a WebSocket and registers the client with the Hub.
-// The clientID parameter sets the client's identifier for targeted messaging.
-func ServeWS(hub *Hub, writer http.ResponseWriter, requests *http.Request, clientID uuid.UUID) {
+// The clientID parameter sets the client's identifier for targeted messaging and
+// groups lists the groups the client starts out subscribed to.
+func ServeWS(hub *Hub, writer http.ResponseWriter, requests *http.Request, clientID uuid.UUID, groups []uuid.UUID) {
 	upgrader := websocket.Upgrader{
 		ReadBufferSize:  1024,
 		WriteBufferSize: 1024,
@@ -73,14 +307,20 @@ func ServeWS(hub *Hub, writer http.ResponseWriter, requests *http.Request, clien
 		return
 	}
 	client := &Client{
-		hub:  hub,
-		conn: conn,
-		send: make(chan []byte, 256),
-		ID:   clientID,
+		hub:      hub,
+		conn:     conn,
+		send:     make(chan []byte, 256),
+		ID:       clientID,
+		done:     make(chan struct{}),
+		groups:   make(map[uuid.UUID]bool, len(groups)),
+		presence: make(map[uuid.UUID]presenceSent),
+	}
+	for _, groupID := range groups {
+		client.groups[groupID] = true
 	}
 
 	// Register the client with the hub.
-	client.hub.register <- client
+	client.hub.register(client)
 
 	// Start the read and write pumps.
 	go client.writePump()
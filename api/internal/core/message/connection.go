@@ -0,0 +1,145 @@
+package message
+
+import (
+	database "api/internal/core/db"
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// connectionTTL is how long a replica's claim that a user is connected
+	// lasts without a refresh, so a crashed replica stops counting.
+	connectionTTL = 90 * time.Second
+
+	connectionRefreshInterval = 30 * time.Second
+)
+
+// IsOnline reports whether the user has a websocket open on any replica.
+// Other replicas are found through user_connections.
+func (hub *Hub) IsOnline(ctx context.Context, userID uuid.UUID) (bool, error) {
+	if len(hub.users.lookup(userID)) > 0 {
+		return true, nil
+	}
+	return hub.queries.CheckUserOnline(ctx, userID)
+}
+
+// connectionQueue collects the users whose first socket opened or last socket
+// closed on this replica. One worker per queue writes their rows, so a user's
+// rows are written in order, and each write reflects whether the user is
+// connected by the time it runs rather than when it was queued.
+type connectionQueue struct {
+	mutex   sync.Mutex
+	pending map[uuid.UUID]bool
+	wake    chan struct{}
+}
+
+func newConnectionQueue() *connectionQueue {
+	return &connectionQueue{
+		pending: make(map[uuid.UUID]bool),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+func (queue *connectionQueue) push(userID uuid.UUID) {
+	queue.mutex.Lock()
+	queue.pending[userID] = true
+	queue.mutex.Unlock()
+	select {
+	case queue.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (queue *connectionQueue) take() []uuid.UUID {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	userIDs := make([]uuid.UUID, 0, len(queue.pending))
+	for userID := range queue.pending {
+		userIDs = append(userIDs, userID)
+	}
+	clear(queue.pending)
+	return userIDs
+}
+
+// queueConnection schedules a write of the user's connection row.
+func (hub *Hub) queueConnection(userID uuid.UUID) {
+	hub.connections[int(userID[15])%shardCount].push(userID)
+}
+
+// writeConnections runs one queue's worker until ctx is cancelled.
+func (hub *Hub) writeConnections(ctx context.Context, queue *connectionQueue) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-queue.wake:
+			for _, userID := range queue.take() {
+				if len(hub.users.lookup(userID)) > 0 {
+					hub.connect(userID)
+				} else {
+					hub.disconnect(userID)
+				}
+			}
+		}
+	}
+}
+
+// connect records the user's first socket on this replica.
+func (hub *Hub) connect(userID uuid.UUID) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := hub.touchConnections(ctx, []uuid.UUID{userID}); err != nil {
+		log.Printf("Failed to record connection for user %s: %v", userID, err)
+	}
+}
+
+// disconnect removes the user's connection row once their last socket here
+// closes.
+func (hub *Hub) disconnect(userID uuid.UUID) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	connection := database.DeleteUserConnectionParams{
+		UserID:  userID,
+		Replica: hub.replica,
+	}
+	if err := hub.queries.DeleteUserConnection(ctx, connection); err != nil {
+		log.Printf("Failed to remove connection for user %s: %v", userID, err)
+	}
+}
+
+func (hub *Hub) touchConnections(ctx context.Context, userIDs []uuid.UUID) error {
+	return hub.queries.TouchUserConnections(ctx, database.TouchUserConnectionsParams{
+		UserIds:   userIDs,
+		Replica:   hub.replica,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(connectionTTL), Valid: true},
+	})
+}
+
+// maintainConnections refreshes the connections of every user with a socket
+// here and clears rows that expired, including those of dead replicas.
+func (hub *Hub) maintainConnections(ctx context.Context) {
+	ticker := time.NewTicker(connectionRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refreshContext, cancel := context.WithTimeout(ctx, 5*time.Second)
+			if userIDs := hub.users.keys(); len(userIDs) > 0 {
+				if err := hub.touchConnections(refreshContext, userIDs); err != nil {
+					log.Printf("Failed to refresh user connections: %v", err)
+				}
+			}
+			if err := hub.queries.DeleteExpiredUserConnections(refreshContext); err != nil {
+				log.Printf("Failed to delete expired user connections: %v", err)
+			}
+			cancel()
+		}
+	}
+}
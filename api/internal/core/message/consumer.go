@@ -1,57 +1,207 @@
 package message
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/IBM/sarama"
 	"github.com/google/uuid"
 )
 
+const (
+	// Backoff between attempts to reach Kafka.
+	minConsumerBackoff = time.Second
+	maxConsumerBackoff = time.Minute
+)
+
+// KafkaBrokers returns the Kafka addresses from KAFKA_BROKERS, a comma
+// separated list, falling back to the broker in docker compose.
+func KafkaBrokers() []string {
+	value := os.Getenv("KAFKA_BROKERS")
+	if value == "" {
+		return []string{"broker:9092"}
+	}
+	var brokers []string
+	for _, broker := range strings.Split(value, ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			brokers = append(brokers, broker)
+		}
+	}
+	return brokers
+}
+
+// consumerGroupID is the Kafka consumer group this replica joins. Replicas
+// share KAFKA_CONSUMER_GROUP and split the partitions between them, which is
+// enough while there is only one. With more, every replica has to read every
+// partition to reach the sockets connected to it, so each one sets
+// KAFKA_INSTANCE_ID and gets a group of its own.
+//
+// The instance ID has to be stable, such as a StatefulSet pod name, so a
+// restarted replica picks its group and committed offsets back up instead of
+// leaving a new one behind each time. Groups of instances that are scaled
+// away are not removed by the API; once empty, Kafka drops their offsets
+// after the broker's offsets.retention.minutes (7 days by default).
+func consumerGroupID() string {
+	groupID := os.Getenv("KAFKA_CONSUMER_GROUP")
+	if groupID == "" {
+		groupID = "api-realtime"
+	}
+	if instanceID := os.Getenv("KAFKA_INSTANCE_ID"); instanceID != "" {
+		return groupID + "-" + instanceID
+	}
+	return groupID
+}
+
+// SetupKafkaConsumer joins this replica's consumer group in the background and
+// returns immediately. While Kafka is unreachable realtime delivery is paused
+// and the consumer keeps retrying with backoff; the API itself keeps serving.
 func SetupKafkaConsumer(hub *Hub) {
-	// Set up Sarama consumer configuration.
 	config := sarama.NewConfig()
 	config.Consumer.Return.Errors = true
+	// A new group starts at the end of the topic; clients catch up on older
+	// events through resume.
+	config.Consumer.Offsets.Initial = sarama.OffsetNewest
+	config.Consumer.Offsets.AutoCommit.Enable = true
+	config.Consumer.Offsets.AutoCommit.Interval = time.Second
+	config.Consumer.Group.Rebalance.GroupStrategies = []sarama.BalanceStrategy{
+		sarama.NewBalanceStrategyRoundRobin(),
+	}
 
-	// List of Kafka broker addresses.
-	brokers := []string{"broker:9092"}
-	// The topic to consume post events from.
+	go runConsumerGroup(context.Background(), hub, KafkaBrokers(), consumerGroupID(), config)
+}
+
+// runConsumerGroup keeps a consumer group session alive until ctx is done,
+// reconnecting with exponential backoff whenever Kafka fails.
+func runConsumerGroup(ctx context.Context, hub *Hub, brokers []string, groupID string, config *sarama.Config) {
+	handler := postsHandler{hub: hub}
+	backoff := minConsumerBackoff
+	for ctx.Err() == nil {
+		group, err := sarama.NewConsumerGroup(brokers, groupID, config)
+		if err != nil {
+			log.Printf("Kafka consumer unavailable, retrying in %s: %v", backoff, err)
+			if !sleepContext(ctx, backoff) {
+				return
+			}
+			backoff = min(backoff*2, maxConsumerBackoff)
+			continue
+		}
+		go func() {
+			for err := range group.Errors() {
+				log.Printf("Kafka consumer error: %v", err)
+			}
+		}()
+
+		// Consume returns whenever the group rebalances, so keep calling it
+		// until it fails.
+		for ctx.Err() == nil {
+			err = group.Consume(ctx, []string{POST_TOPIC}, handler)
+			if err != nil {
+				break
+			}
+			backoff = minConsumerBackoff
+		}
+		if closeErr := group.Close(); closeErr != nil {
+			log.Printf("Error closing kafka consumer group: %v", closeErr)
+		}
+		if err == nil || errors.Is(err, sarama.ErrClosedConsumerGroup) {
+			continue
+		}
 
-	// Create a new consumer.
-	consumer, err := sarama.NewConsumer(brokers, config)
-	if err != nil {
-		log.Printf("Error creating kafka consumer %s", err.Error())
-		return
+		log.Printf("Kafka consumer failed, reconnecting in %s: %v", backoff, err)
+		if !sleepContext(ctx, backoff) {
+			return
+		}
+		backoff = min(backoff*2, maxConsumerBackoff)
 	}
+}
 
-	setupPostsConsumer(consumer, hub)
-	// For demonstration, we consume from partition 0 starting at the newest offset.
+func sleepContext(ctx context.Context, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
 }
 
+// parseKafkaMessage routes a message by its envelope. The key, which is
+// either "group:<groupId>" or "user:<userId>", must agree with it.
 func parseKafkaMessage(msg *sarama.ConsumerMessage) (*WSMessage, error) {
-	//Somehow parse the key to get groupId or someshit
-
-	messageKey := string(msg.Key)
-	parts := strings.Split(messageKey, ":")
-	subject, subjectId := parts[0], parts[1]
+	return ParseMessage(string(msg.Key), msg.Value)
+}
 
-	target, parseErr := uuid.Parse(subjectId)
-	if parseErr != nil {
-		return nil, parseErr
+// ParseMessage validates an encoded Envelope and builds the WSMessage that
+// delivers it. It is shared with brokers that deliver events without going
+// through Kafka.
+func ParseMessage(messageKey string, value []byte) (*WSMessage, error) {
+	var envelope Envelope
+	if err := json.Unmarshal(value, &envelope); err != nil {
+		return nil, fmt.Errorf("malformed event envelope: %w", err)
+	}
+	if err := envelope.Validate(); err != nil {
+		return nil, err
+	}
+	if envelope.Key() != messageKey {
+		return nil, fmt.Errorf("kafka message key %q does not match event %s", messageKey, envelope.ID)
 	}
 
-	var message WSMessage
-	if subject == "group" {
-		message = WSMessage{
-			Group: &target,
-			Data:  msg.Value,
-		}
-	} else {
-		message = WSMessage{
-			Target: &target,
-			Data:   msg.Value,
-		}
+	return &WSMessage{
+		Target:   envelope.User,
+		Group:    envelope.Group,
+		Data:     value,
+		Sequence: envelope.Sequence,
+		Revoke:   revocation(envelope),
+		Author:   author(envelope),
+	}, nil
+}
+
+// author reads who wrote the post in a post.published or post.updated event,
+// so the post can be kept from users the author has blocked.
+func author(envelope Envelope) *uuid.UUID {
+	switch envelope.Type {
+	case EventPostPublished, EventPostUpdated:
+	default:
+		return nil
+	}
+	var post struct {
+		UserID uuid.UUID `json:"userId"`
+	}
+	if err := json.Unmarshal(envelope.Data, &post); err != nil {
+		return nil
 	}
+	return &post.UserID
+}
 
-	return &message, nil
+// revocation reads who loses access to which group from a member.left,
+// member.removed or group.deleted event. group.deleted is addressed to each
+// former member, so the user comes from the envelope.
+func revocation(envelope Envelope) *Revocation {
+	switch envelope.Type {
+	case EventMemberLeft, EventMemberRemoved, EventGroupDeleted:
+	default:
+		return nil
+	}
+	var ref struct {
+		GroupID uuid.UUID  `json:"groupId"`
+		UserID  *uuid.UUID `json:"userId"`
+	}
+	if err := json.Unmarshal(envelope.Data, &ref); err != nil {
+		return nil
+	}
+	user := ref.UserID
+	if user == nil {
+		user = envelope.User
+	}
+	if user == nil {
+		return nil
+	}
+	return &Revocation{Group: ref.GroupID, User: *user}
 }
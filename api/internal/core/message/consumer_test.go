@@ -0,0 +1,57 @@
+package message
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestRevocation(t *testing.T) {
+	groupID := uuid.New()
+	userID := uuid.New()
+	memberData := json.RawMessage(`{"groupId":"` + groupID.String() + `","userId":"` + userID.String() + `"}`)
+	groupData := json.RawMessage(`{"groupId":"` + groupID.String() + `"}`)
+
+	tests := []struct {
+		name     string
+		envelope Envelope
+		want     *Revocation
+	}{
+		{name: "member left", envelope: Envelope{Type: EventMemberLeft, Group: &groupID, Data: memberData}, want: &Revocation{Group: groupID, User: userID}},
+		{name: "member removed", envelope: Envelope{Type: EventMemberRemoved, User: &userID, Data: memberData}, want: &Revocation{Group: groupID, User: userID}},
+		{name: "group deleted", envelope: Envelope{Type: EventGroupDeleted, User: &userID, Data: groupData}, want: &Revocation{Group: groupID, User: userID}},
+		{name: "other event", envelope: Envelope{Type: EventMemberJoined, Group: &groupID, Data: memberData}, want: nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := revocation(test.envelope)
+			if (got == nil) != (test.want == nil) || (got != nil && *got != *test.want) {
+				t.Errorf("revocation() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestConsumerGroupID(t *testing.T) {
+	tests := []struct {
+		name       string
+		group      string
+		instanceID string
+		want       string
+	}{
+		{name: "default shared group", want: "api-realtime"},
+		{name: "configured shared group", group: "realtime", want: "realtime"},
+		{name: "default group per instance", instanceID: "api-0", want: "api-realtime-api-0"},
+		{name: "configured group per instance", group: "realtime", instanceID: "api-1", want: "realtime-api-1"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("KAFKA_CONSUMER_GROUP", test.group)
+			t.Setenv("KAFKA_INSTANCE_ID", test.instanceID)
+			if got := consumerGroupID(); got != test.want {
+				t.Errorf("consumerGroupID() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
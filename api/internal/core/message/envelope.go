@@ -0,0 +1,103 @@
+package message
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	EventPostPublished   EventType = "post.published"
+	EventPostUpdated     EventType = "post.updated"
+	EventPostRemoved     EventType = "post.removed"
+	EventPostDeleted     EventType = "post.deleted"
+	EventMemberJoined    EventType = "member.joined"
+	EventMemberLeft      EventType = "member.left"
+	EventMemberRemoved   EventType = "member.removed"
+	EventGroupDeleted    EventType = "group.deleted"
+	EventReactionAdded   EventType = "reaction.added"
+	EventReactionRemoved EventType = "reaction.removed"
+	EventCommentCreated  EventType = "comment.created"
+	EventCommentUpdated  EventType = "comment.updated"
+	EventCommentDeleted  EventType = "comment.deleted"
+	EventDirectMessage   EventType = "dm.created"
+	EventDirectRead      EventType = "dm.read"
+)
+
+// Envelope wraps every realtime event. Clients deduplicate on ID and use
+// Sequence, which counts up per group or per user, to notice missed events.
+// Exactly one of Group or User is set and decides who receives the event.
+type Envelope struct {
+	ID        uuid.UUID       `json:"id"`
+	Type      EventType       `json:"type"`
+	Version   int             `json:"version"`
+	Timestamp time.Time       `json:"timestamp"`
+	Sequence  int64           `json:"sequence"`
+	Group     *uuid.UUID      `json:"group,omitempty"`
+	User      *uuid.UUID      `json:"user,omitempty"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// NewEnvelope encodes data as the current version of eventType. The caller
+// still has to address the envelope and give it a sequence.
+func NewEnvelope(eventType EventType, data any) (Envelope, error) {
+	schema, known := schemas[eventType]
+	if !known {
+		return Envelope{}, fmt.Errorf("unknown event type %q", eventType)
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return Envelope{}, err
+	}
+	return Envelope{
+		ID:        uuid.New(),
+		Type:      eventType,
+		Version:   schema.Version,
+		Timestamp: time.Now().UTC(),
+		Data:      payload,
+	}, nil
+}
+
+// Validate checks the envelope's header and its data against the schema of
+// its event type.
+func (envelope Envelope) Validate() error {
+	if envelope.ID == uuid.Nil {
+		return errors.New("event is missing an id")
+	}
+	if envelope.Timestamp.IsZero() {
+		return errors.New("event is missing a timestamp")
+	}
+	if envelope.Sequence <= 0 {
+		return errors.New("event is missing a sequence")
+	}
+	if (envelope.Group == nil) == (envelope.User == nil) {
+		return errors.New("event must be addressed to exactly one group or user")
+	}
+	schema, known := schemas[envelope.Type]
+	if !known {
+		return fmt.Errorf("unknown event type %q", envelope.Type)
+	}
+	if envelope.Version < 1 || envelope.Version > schema.Version {
+		return fmt.Errorf("unsupported version %d of %s", envelope.Version, envelope.Type)
+	}
+	if err := schema.validate(envelope.Data); err != nil {
+		return fmt.Errorf("invalid %s data: %w", envelope.Type, err)
+	}
+	return nil
+}
+
+// Key is the message key the envelope is published under.
+func (envelope Envelope) Key() string {
+	if envelope.Group != nil {
+		return "group:" + envelope.Group.String()
+	}
+	if envelope.User != nil {
+		return "user:" + envelope.User.String()
+	}
+	return ""
+}
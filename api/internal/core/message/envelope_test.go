@@ -0,0 +1,139 @@
+package message
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestEnvelopeValidate(t *testing.T) {
+	groupID := uuid.New()
+	userID := uuid.New()
+	postData := `{"id":"` + uuid.NewString() + `","userId":"` + userID.String() + `","media":"TEXT","status":"PUBLISHED"}`
+
+	valid := func() Envelope {
+		return Envelope{
+			ID:        uuid.New(),
+			Type:      EventPostPublished,
+			Version:   1,
+			Timestamp: time.Now(),
+			Sequence:  1,
+			Group:     &groupID,
+			Data:      json.RawMessage(postData),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*Envelope)
+		wantErr string
+	}{
+		{name: "valid group event", modify: func(*Envelope) {}},
+		{name: "valid user event", modify: func(e *Envelope) {
+			e.Group = nil
+			e.User = &userID
+		}},
+		{name: "missing id", modify: func(e *Envelope) { e.ID = uuid.Nil }, wantErr: "missing an id"},
+		{name: "missing timestamp", modify: func(e *Envelope) { e.Timestamp = time.Time{} }, wantErr: "missing a timestamp"},
+		{name: "missing sequence", modify: func(e *Envelope) { e.Sequence = 0 }, wantErr: "missing a sequence"},
+		{name: "no recipient", modify: func(e *Envelope) { e.Group = nil }, wantErr: "exactly one group or user"},
+		{name: "both recipients", modify: func(e *Envelope) { e.User = &userID }, wantErr: "exactly one group or user"},
+		{name: "unknown type", modify: func(e *Envelope) { e.Type = "post.liked" }, wantErr: "unknown event type"},
+		{name: "future version", modify: func(e *Envelope) { e.Version = 2 }, wantErr: "unsupported version"},
+		{name: "zero version", modify: func(e *Envelope) { e.Version = 0 }, wantErr: "unsupported version"},
+		{name: "data not an object", modify: func(e *Envelope) { e.Data = json.RawMessage(`[]`) }, wantErr: "not an object"},
+		{name: "missing required field", modify: func(e *Envelope) {
+			e.Data = json.RawMessage(`{"id":"` + uuid.NewString() + `","media":"TEXT","status":"PUBLISHED"}`)
+		}, wantErr: "missing userId"},
+		{name: "null required field", modify: func(e *Envelope) {
+			e.Data = json.RawMessage(`{"id":null,"userId":"` + userID.String() + `","media":"TEXT","status":"PUBLISHED"}`)
+		}, wantErr: "missing id"},
+		{name: "bad uuid", modify: func(e *Envelope) {
+			e.Data = json.RawMessage(`{"id":"nope","userId":"` + userID.String() + `","media":"TEXT","status":"PUBLISHED"}`)
+		}, wantErr: "id is not a uuid"},
+		{name: "bad timestamp", modify: func(e *Envelope) {
+			e.Data = json.RawMessage(strings.TrimSuffix(postData, "}") + `,"dateCreated":"yesterday"}`)
+		}, wantErr: "dateCreated is not a timestamp"},
+		{name: "non-string property", modify: func(e *Envelope) {
+			e.Data = json.RawMessage(strings.TrimSuffix(postData, "}") + `,"caption":3}`)
+		}, wantErr: "caption must be a string"},
+		{name: "optional property null", modify: func(e *Envelope) {
+			e.Data = json.RawMessage(strings.TrimSuffix(postData, "}") + `,"caption":null}`)
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			envelope := valid()
+			test.modify(&envelope)
+			err := envelope.Validate()
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("Validate() = %v, want error containing %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewEnvelope(t *testing.T) {
+	envelope, err := NewEnvelope(EventGroupDeleted, map[string]string{"groupId": uuid.NewString()})
+	if err != nil {
+		t.Fatalf("NewEnvelope() = %v", err)
+	}
+	if envelope.ID == uuid.Nil || envelope.Timestamp.IsZero() {
+		t.Errorf("NewEnvelope() left the header empty: %+v", envelope)
+	}
+	if envelope.Version != schemas[EventGroupDeleted].Version {
+		t.Errorf("Version = %d, want %d", envelope.Version, schemas[EventGroupDeleted].Version)
+	}
+
+	if _, err := NewEnvelope("group.renamed", nil); err == nil {
+		t.Error("NewEnvelope() accepted an unknown event type")
+	}
+}
+
+func TestEnvelopeKey(t *testing.T) {
+	groupID := uuid.New()
+	userID := uuid.New()
+	tests := []struct {
+		name     string
+		envelope Envelope
+		want     string
+	}{
+		{name: "group", envelope: Envelope{Group: &groupID}, want: "group:" + groupID.String()},
+		{name: "user", envelope: Envelope{User: &userID}, want: "user:" + userID.String()},
+		{name: "unaddressed", envelope: Envelope{}, want: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.envelope.Key(); got != test.want {
+				t.Errorf("Key() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSchemasCoverEventTypes(t *testing.T) {
+	for eventType, schema := range schemas {
+		if schema.Version < 1 {
+			t.Errorf("%s has version %d", eventType, schema.Version)
+		}
+		for _, name := range schema.Required {
+			if _, ok := schema.Properties[name]; !ok {
+				t.Errorf("%s requires %s, which it doesn't describe", eventType, name)
+			}
+		}
+	}
+	documents := JSONSchemas()
+	if len(documents) != len(schemas) {
+		t.Errorf("JSONSchemas() has %d documents, want %d", len(documents), len(schemas))
+	}
+}
@@ -1,8 +1,14 @@
 package message
 
 import (
-	"slices"
+	database "api/internal/core/db"
+	"context"
+	"fmt"
+	"log"
+	"os"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -13,111 +19,249 @@ type WSMessage struct {
 	Target *uuid.UUID `json:"target"` // ID of the target WS client (end-user)
 	Group  *uuid.UUID `json:"group"`  // ID of the group of WS clients
 	Data   []byte     `json:"data"`   // The payload to be sent
+	// Sequence is the event's position in its group, used to skip live events
+	// a resuming client already got through replay.
+	Sequence int64 `json:"sequence"`
+	// Revoke is set by membership events. Once the event is delivered the
+	// user loses their subscriptions to the group on this replica.
+	Revoke *Revocation `json:"revoke,omitempty"`
+	// Author is set by post events and keeps them from users the author
+	// has blocked.
+	Author *uuid.UUID `json:"author,omitempty"`
 }
 
-type Hub struct {
-	// Registered clients.
-	clients map[*Client]bool
-
-	// Channel for messages to be broadcast.
-	broadcast chan WSMessage
-
-	// Register requests from clients.
-	register chan *Client
-
-	// Unregister requests from clients.
-	unregister chan *Client
-
-	// Mutex to protect the clients map.
-	mutex sync.RWMutex
-}
-
-func NewHub() *Hub {
-	return &Hub{
-		clients:    make(map[*Client]bool),
-		broadcast:  make(chan WSMessage),
-		register:   make(chan *Client),
-		unregister: make(chan *Client),
-	}
-}
-
-func (hub *Hub) Run() {
-	for {
-		select {
-		case client := <-hub.register:
-			hub.mutex.Lock()
-			hub.clients[client] = true
-			hub.mutex.Unlock()
-
-		case client := <-hub.unregister:
-			hub.mutex.Lock()
-			if _, ok := hub.clients[client]; ok {
-				delete(hub.clients, client)
-				close(client.send)
-			}
-			hub.mutex.Unlock()
-
-		case msg := <-hub.broadcast:
-			if msg.Target != nil {
-				// Send only to the client(s) with a matching ID.
-				hub.mutex.RLock()
-				for client := range hub.clients {
-					handleBroadcastMessage(msg, client, hub)
-				}
-				hub.mutex.RUnlock()
-			} else {
-				// Broadcast to all clients.
-				hub.mutex.RLock()
-				for client := range hub.clients {
-					select {
-					case client.send <- msg.Data:
-					default:
-						close(client.send)
-						delete(hub.clients, client)
-					}
-				}
-				hub.mutex.RUnlock()
-			}
+// Revocation ends a user's access to a group's live events.
+type Revocation struct {
+	Group uuid.UUID `json:"group"`
+	User  uuid.UUID `json:"user"`
+}
+
+// shardCount spreads the indexes over several locks so registrations and
+// deliveries for unrelated users and groups don't contend.
+const shardCount = 32
+
+type shard struct {
+	mutex   sync.RWMutex
+	clients map[uuid.UUID]map[*Client]bool
+}
+
+// clientIndex maps a user or group ID to the clients listening for it.
+type clientIndex struct {
+	shards [shardCount]shard
+}
+
+func newClientIndex() *clientIndex {
+	index := &clientIndex{}
+	for i := range index.shards {
+		index.shards[i].clients = make(map[uuid.UUID]map[*Client]bool)
+	}
+	return index
+}
+
+func (index *clientIndex) shardFor(key uuid.UUID) *shard {
+	// The last byte of a v4 UUID is random.
+	return &index.shards[int(key[15])%shardCount]
+}
+
+// add and remove return how many clients are left listening for key.
+func (index *clientIndex) add(key uuid.UUID, client *Client) int {
+	shard := index.shardFor(key)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+	if shard.clients[key] == nil {
+		shard.clients[key] = make(map[*Client]bool)
+	}
+	shard.clients[key][client] = true
+	return len(shard.clients[key])
+}
+
+func (index *clientIndex) remove(key uuid.UUID, client *Client) int {
+	shard := index.shardFor(key)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+	delete(shard.clients[key], client)
+	if len(shard.clients[key]) == 0 {
+		delete(shard.clients, key)
+	}
+	return len(shard.clients[key])
+}
+
+// keys lists every ID with at least one client.
+func (index *clientIndex) keys() []uuid.UUID {
+	var keys []uuid.UUID
+	for i := range index.shards {
+		shard := &index.shards[i]
+		shard.mutex.RLock()
+		for key := range shard.clients {
+			keys = append(keys, key)
 		}
+		shard.mutex.RUnlock()
 	}
+	return keys
 }
 
-// Broadcast pushes a WSMessage into the hub's broadcast channel.
-func (hub *Hub) Broadcast(msg WSMessage) {
-	hub.broadcast <- msg
+// lookup copies the clients for key so delivery happens without the lock.
+func (index *clientIndex) lookup(key uuid.UUID) []*Client {
+	shard := index.shardFor(key)
+	shard.mutex.RLock()
+	defer shard.mutex.RUnlock()
+	clients := make([]*Client, 0, len(shard.clients[key]))
+	for client := range shard.clients[key] {
+		clients = append(clients, client)
+	}
+	return clients
 }
 
-// NumClients returns the number of currently connected clients.
-func (hub *Hub) NumClients() int {
-	hub.mutex.RLock()
-	defer hub.mutex.RUnlock()
-	return len(hub.clients)
+// Hub tracks connected clients by user and by the groups they subscribe to,
+// so a message only visits the clients it is addressed to.
+type Hub struct {
+	users  *clientIndex
+	groups *clientIndex
+
+	numClients atomic.Int64
+
+	presence *presenceTracker
+
+	// connections queues writes to user_connections, sharded like users.
+	connections [shardCount]*connectionQueue
+
+	// replica names this process in user_connections.
+	replica string
+
+	// queries checks memberships on subscribe and reads the retained group
+	// events replayed to resuming clients.
+	queries *database.Queries
+}
+
+func NewHub(queries *database.Queries) *Hub {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "api"
+	}
+	hub := &Hub{
+		users:    newClientIndex(),
+		groups:   newClientIndex(),
+		presence: newPresenceTracker(),
+		replica:  fmt.Sprintf("%s-%s", hostname, uuid.NewString()),
+		queries:  queries,
+	}
+	for i := range hub.connections {
+		hub.connections[i] = newConnectionQueue()
+	}
+	return hub
 }
 
-func handleBroadcastMessage(msg WSMessage, client *Client, hub *Hub) {
-	if msg.Target != nil {
-		if client.ID != *msg.Target {
+// Broadcast delivers a WSMessage to its target user or group. Clients that
+// can't keep up are disconnected rather than slowing everyone else down.
+func (hub *Hub) Broadcast(msg WSMessage) {
+	var recipients []*Client
+	switch {
+	case msg.Target != nil:
+		recipients = hub.users.lookup(*msg.Target)
+	case msg.Group != nil:
+		recipients = hub.groups.lookup(*msg.Group)
+	default:
+		log.Println("Dropping websocket message without a target or group")
+		return
+	}
+
+	var blocked map[uuid.UUID]bool
+	if msg.Author != nil {
+		var ok bool
+		if blocked, ok = hub.blockedBy(*msg.Author); !ok {
 			return
 		}
-
-		select {
-		case client.send <- msg.Data:
-		default:
-			// If the client can’t receive the message, close the connection.
-			close(client.send)
-			delete(hub.clients, client)
+	}
+	for _, client := range recipients {
+		if blocked[client.ID] {
+			continue
 		}
+		if !client.deliver(msg) {
+			log.Printf("Dropping slow websocket client %s", client.ID)
+			client.close()
+		}
+	}
+	if msg.Revoke != nil {
+		hub.revoke(*msg.Revoke)
+	}
+}
 
-	} else if msg.Group != nil {
-		if !slices.Contains(client.Groups, *msg.Group) {
-			return
+// blockedBy returns the users the author has blocked. If they can't be
+// looked up the post is not delivered to anyone rather than risk showing it
+// to a blocked user; clients pick it up on their next refetch.
+func (hub *Hub) blockedBy(author uuid.UUID) (map[uuid.UUID]bool, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	blockedIDs, err := hub.queries.ListBlockedUserIDs(ctx, author)
+	if err != nil {
+		log.Printf("Failed to fetch users blocked by %s: %v", author, err)
+		return nil, false
+	}
+	blocked := make(map[uuid.UUID]bool, len(blockedIDs))
+	for _, blockedID := range blockedIDs {
+		blocked[blockedID] = true
+	}
+	return blocked, true
+}
+
+// revoke unsubscribes the user's sockets from the group and ends their SSE
+// streams of it, since those only follow that one group. Their presence in
+// the group is cleared as well.
+func (hub *Hub) revoke(revocation Revocation) {
+	for _, client := range hub.groups.lookup(revocation.Group) {
+		if client.ID != revocation.User {
+			continue
 		}
-		select {
-		case client.send <- msg.Data:
-		default:
-			// If the client can’t receive the message, close the connection.
-			close(client.send)
-			delete(hub.clients, client)
+		if client.groupOnly {
+			client.close()
+			continue
 		}
+		hub.unsubscribe(client, revocation.Group)
+		client.mutex.Lock()
+		delete(client.presence, revocation.Group)
+		client.mutex.Unlock()
+	}
+	hub.applyPresence(PresenceUpdate{
+		Group: revocation.Group,
+		User:  revocation.User,
+		State: PresenceAway,
+	})
+}
+
+// NumClients returns the number of currently connected clients.
+func (hub *Hub) NumClients() int {
+	return int(hub.numClients.Load())
+}
+
+func (hub *Hub) register(client *Client) {
+	if !client.groupOnly && hub.users.add(client.ID, client) == 1 {
+		hub.queueConnection(client.ID)
+	}
+	for _, groupID := range client.subscriptions() {
+		hub.groups.add(groupID, client)
+	}
+	hub.numClients.Add(1)
+}
+
+func (hub *Hub) unregister(client *Client) {
+	if !client.groupOnly && hub.users.remove(client.ID, client) == 0 {
+		hub.queueConnection(client.ID)
+	}
+	for _, groupID := range client.subscriptions() {
+		hub.groups.remove(groupID, client)
+	}
+	hub.numClients.Add(-1)
+}
+
+func (hub *Hub) subscribe(client *Client, groupID uuid.UUID) {
+	if client.addSubscription(groupID) {
+		hub.groups.add(groupID, client)
+	}
+}
+
+func (hub *Hub) unsubscribe(client *Client, groupID uuid.UUID) {
+	if client.removeSubscription(groupID) {
+		hub.groups.remove(groupID, client)
 	}
 }
@@ -0,0 +1,117 @@
+package message
+
+import (
+	database "api/internal/core/db"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// MaxNotificationValue keeps a NOTIFY payload, which Postgres caps just under
+// 8000 bytes, clear of the limit once the notification wrapper is added.
+const MaxNotificationValue = 7000
+
+// Notification is the payload sent on the realtime_events channel when events
+// go through Postgres instead of Kafka. Value holds the encoded Envelope, or
+// for group events too large to NOTIFY, Group and Sequence point at the copy
+// retained in group_events. Presence updates always take this path.
+type Notification struct {
+	Key      string          `json:"key,omitempty"`
+	Value    json.RawMessage `json:"value,omitempty"`
+	Group    *uuid.UUID      `json:"group,omitempty"`
+	Sequence int64           `json:"sequence,omitempty"`
+	Presence *PresenceUpdate `json:"presence,omitempty"`
+}
+
+// SetupPostgresListener listens on the realtime_events channel in the
+// background and returns immediately, so events published by any replica
+// while Kafka is down still reach this replica's hub.
+func SetupPostgresListener(pool *pgxpool.Pool, hub *Hub) {
+	go runPostgresListener(context.Background(), pool, hub)
+}
+
+func runPostgresListener(ctx context.Context, pool *pgxpool.Pool, hub *Hub) {
+	backoff := minConsumerBackoff
+	for ctx.Err() == nil {
+		err := listenForNotifications(ctx, pool, hub, func() { backoff = minConsumerBackoff })
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("Postgres realtime listener failed, reconnecting in %s: %v", backoff, err)
+		if !sleepContext(ctx, backoff) {
+			return
+		}
+		backoff = min(backoff*2, maxConsumerBackoff)
+	}
+}
+
+// listenForNotifications holds one pooled connection for as long as it keeps
+// listening. connected is called once LISTEN succeeds.
+func listenForNotifications(ctx context.Context, pool *pgxpool.Pool, hub *Hub, connected func()) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// The connection is still subscribed to the channel, so don't return it
+	// to the pool.
+	defer conn.Hijack().Close(context.Background())
+
+	if err := database.New(conn.Conn()).ListenRealtimeEvents(ctx); err != nil {
+		return err
+	}
+	connected()
+	queries := database.New(pool)
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		wsMessage, presence, parseErr := parseNotification(ctx, queries, []byte(notification.Payload))
+		if parseErr != nil {
+			log.Printf("Failed to parse realtime notification: %v", parseErr)
+			continue
+		}
+		if presence != nil {
+			hub.applyPresence(*presence)
+			continue
+		}
+		hub.Broadcast(*wsMessage)
+	}
+}
+
+// parseNotification returns either the event to broadcast or a presence
+// update to apply.
+func parseNotification(ctx context.Context, queries *database.Queries, payload []byte) (*WSMessage, *PresenceUpdate, error) {
+	var notification Notification
+	if err := json.Unmarshal(payload, &notification); err != nil {
+		return nil, nil, fmt.Errorf("malformed notification: %w", err)
+	}
+	if notification.Presence != nil {
+		return nil, notification.Presence, nil
+	}
+	if len(notification.Value) > 0 {
+		wsMessage, err := ParseMessage(notification.Key, notification.Value)
+		return wsMessage, nil, err
+	}
+	if notification.Group == nil {
+		return nil, nil, fmt.Errorf("notification for %q carries no event", notification.Key)
+	}
+
+	fetchContext, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	getEvent := database.GetGroupEventParams{
+		GroupID:  *notification.Group,
+		Sequence: notification.Sequence,
+	}
+	event, err := queries.GetGroupEvent(fetchContext, getEvent)
+	if err != nil {
+		return nil, nil, err
+	}
+	wsMessage, err := ParseMessage(notification.Key, event.Payload)
+	return wsMessage, nil, err
+}
@@ -4,39 +4,41 @@ import (
 	"log"
 
 	"github.com/IBM/sarama"
-	"github.com/gin-gonic/gin"
 )
 
 const POST_TOPIC = "posts"
 
-func setupPostsConsumer(consumer sarama.Consumer, hub *Hub) {
+// postsHandler forwards every message on the topic to the hub. Offsets are marked as soon as a message is handed over, since a
+// client that misses it can still replay it from the group event log.
+type postsHandler struct {
+	hub *Hub
+}
 
-	postConsumer, err := consumer.ConsumePartition(POST_TOPIC, 0, sarama.OffsetNewest)
-	if err != nil {
-		log.Fatal("Error creating partition consumer" + err.Error())
-		return
-	}
-	go func() {
-		// Ensure we clean up when this goroutine exits.
-		defer func() {
-			if err := postConsumer.Close(); err != nil {
-				gin.DefaultWriter.Write([]byte("Error closing parition consumer" + err.Error()))
-			}
-		}()
+func (handler postsHandler) Setup(session sarama.ConsumerGroupSession) error {
+	log.Printf("Kafka consumer claimed partitions %v", session.Claims()[POST_TOPIC])
+	return nil
+}
 
-		for {
-			select {
-			case err := <-postConsumer.Errors():
-				gin.DefaultWriter.Write([]byte("Kafka consumer error" + err.Error()))
-			case msg := <-postConsumer.Messages():
-				wsMessage, err := parseKafkaMessage(msg)
-				if err != nil {
-					gin.DefaultWriter.Write([]byte("Failed to parse kafka message" + err.Error()))
-				}
-				if wsMessage != nil {
-					hub.Broadcast(*wsMessage)
-				}
+func (handler postsHandler) Cleanup(session sarama.ConsumerGroupSession) error {
+	return nil
+}
+
+func (handler postsHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for {
+		select {
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
 			}
+			wsMessage, err := parseKafkaMessage(msg)
+			if err != nil {
+				log.Printf("Failed to parse kafka message at %s/%d/%d: %v", msg.Topic, msg.Partition, msg.Offset, err)
+			} else {
+				handler.hub.Broadcast(*wsMessage)
+			}
+			session.MarkMessage(msg, "")
+		case <-session.Context().Done():
+			return nil
 		}
-	}()
+	}
 }
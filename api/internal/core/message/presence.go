@@ -0,0 +1,270 @@
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PresenceState string
+
+const (
+	PresenceViewing   PresenceState = "viewing"
+	PresenceComposing PresenceState = "composing"
+	// PresenceAway clears the user's presence in the group.
+	PresenceAway PresenceState = "away"
+)
+
+const (
+	// presenceTTL is how long a state lasts without being refreshed. Clients
+	// should resend their state well within it.
+	presenceTTL = 30 * time.Second
+
+	// presenceRefreshInterval throttles a client resending an unchanged state.
+	presenceRefreshInterval = 10 * time.Second
+
+	presenceSweepInterval = 5 * time.Second
+)
+
+func (state PresenceState) valid() bool {
+	switch state {
+	case PresenceViewing, PresenceComposing, PresenceAway:
+		return true
+	}
+	return false
+}
+
+// Presence is one user's current state in a group.
+type Presence struct {
+	UserID    uuid.UUID     `json:"userId"`
+	State     PresenceState `json:"state"`
+	ExpiresAt time.Time     `json:"expiresAt"`
+}
+
+// PresenceUpdate is relayed to every replica through Postgres so each one
+// holds the presence of the whole group, not only of its own sockets.
+type PresenceUpdate struct {
+	Group     uuid.UUID     `json:"group"`
+	User      uuid.UUID     `json:"user"`
+	State     PresenceState `json:"state"`
+	ExpiresAt time.Time     `json:"expiresAt"`
+}
+
+// presenceSnapshot is sent to a group's sockets whenever someone's state in
+// it changes. Presence is ephemeral, so it isn't sequenced or retained.
+type presenceSnapshot struct {
+	Type    string     `json:"type"`
+	Group   uuid.UUID  `json:"group"`
+	Members []Presence `json:"members"`
+}
+
+// presenceTracker holds the unexpired presence of every user per group.
+type presenceTracker struct {
+	mutex  sync.Mutex
+	groups map[uuid.UUID]map[uuid.UUID]Presence
+}
+
+func newPresenceTracker() *presenceTracker {
+	return &presenceTracker{groups: make(map[uuid.UUID]map[uuid.UUID]Presence)}
+}
+
+// apply records an update and reports whether anything visible changed, so
+// refreshes of the same state don't trigger a snapshot.
+func (tracker *presenceTracker) apply(update PresenceUpdate) bool {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	members := tracker.groups[update.Group]
+	current, existed := members[update.User]
+
+	if update.State == PresenceAway || !update.ExpiresAt.After(time.Now()) {
+		if !existed {
+			return false
+		}
+		delete(members, update.User)
+		if len(members) == 0 {
+			delete(tracker.groups, update.Group)
+		}
+		return true
+	}
+
+	if members == nil {
+		members = make(map[uuid.UUID]Presence)
+		tracker.groups[update.Group] = members
+	}
+	members[update.User] = Presence{
+		UserID:    update.User,
+		State:     update.State,
+		ExpiresAt: update.ExpiresAt,
+	}
+	return !existed || current.State != update.State
+}
+
+// expire drops stale entries and returns the groups that changed.
+func (tracker *presenceTracker) expire(now time.Time) []uuid.UUID {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	var changed []uuid.UUID
+	for groupID, members := range tracker.groups {
+		before := len(members)
+		for userID, presence := range members {
+			if !presence.ExpiresAt.After(now) {
+				delete(members, userID)
+			}
+		}
+		if len(members) != before {
+			changed = append(changed, groupID)
+		}
+		if len(members) == 0 {
+			delete(tracker.groups, groupID)
+		}
+	}
+	return changed
+}
+
+func (tracker *presenceTracker) snapshot(groupID uuid.UUID) []Presence {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	now := time.Now()
+	members := make([]Presence, 0, len(tracker.groups[groupID]))
+	for _, presence := range tracker.groups[groupID] {
+		if presence.ExpiresAt.After(now) {
+			members = append(members, presence)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].UserID.String() < members[j].UserID.String()
+	})
+	return members
+}
+
+// Start expires presence and keeps this replica's user connections fresh in
+// the background until ctx is cancelled. It returns immediately.
+func (hub *Hub) Start(ctx context.Context) {
+	go hub.maintainConnections(ctx)
+	for _, queue := range hub.connections {
+		go hub.writeConnections(ctx, queue)
+	}
+	go func() {
+		ticker := time.NewTicker(presenceSweepInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				for _, groupID := range hub.presence.expire(now) {
+					hub.broadcastPresence(groupID)
+				}
+			}
+		}
+	}()
+}
+
+// Online returns who is currently present in a group on any replica.
+func (hub *Hub) Online(groupID uuid.UUID) []Presence {
+	return hub.presence.snapshot(groupID)
+}
+
+// publishPresence relays an update to every replica, this one included,
+// through the realtime_events channel.
+func (hub *Hub) publishPresence(update PresenceUpdate) {
+	payload, err := json.Marshal(Notification{Presence: &update})
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		err = hub.queries.NotifyRealtimeEvent(ctx, string(payload))
+	}
+	if err != nil {
+		// Other replicas miss the update, but this one can still show it.
+		log.Printf("Failed to relay presence for group %s: %v", update.Group, err)
+		hub.applyPresence(update)
+	}
+}
+
+// applyPresence records an update from any replica and sends the new
+// snapshot to the group's sockets on this one.
+func (hub *Hub) applyPresence(update PresenceUpdate) {
+	if hub.presence.apply(update) {
+		hub.broadcastPresence(update.Group)
+	}
+}
+
+func (hub *Hub) broadcastPresence(groupID uuid.UUID) {
+	payload, err := json.Marshal(presenceSnapshot{
+		Type:    "presence",
+		Group:   groupID,
+		Members: hub.presence.snapshot(groupID),
+	})
+	if err != nil {
+		log.Printf("Failed to encode presence for group %s: %v", groupID, err)
+		return
+	}
+	hub.Broadcast(WSMessage{Group: &groupID, Data: payload})
+}
+
+// handlePresence relays a state the client reported for one of its groups.
+// Unchanged states are only relayed once per presenceRefreshInterval.
+func (client *Client) handlePresence(groupID uuid.UUID, state PresenceState) {
+	if !state.valid() {
+		client.sendStatus(statusMessage{Type: "error", Group: groupID, Error: "Invalid presence state"})
+		return
+	}
+	if !client.subscribed(groupID) {
+		client.sendStatus(statusMessage{Type: "error", Group: groupID, Error: "Unauthorized"})
+		return
+	}
+
+	now := time.Now()
+	client.mutex.Lock()
+	last, announced := client.presence[groupID]
+	if announced && last.state == state && now.Sub(last.sentAt) < presenceRefreshInterval {
+		client.mutex.Unlock()
+		return
+	}
+	if state == PresenceAway {
+		delete(client.presence, groupID)
+	} else {
+		client.presence[groupID] = presenceSent{state: state, sentAt: now}
+	}
+	client.mutex.Unlock()
+
+	client.hub.publishPresence(PresenceUpdate{
+		Group:     groupID,
+		User:      client.ID,
+		State:     state,
+		ExpiresAt: now.Add(presenceTTL),
+	})
+}
+
+// clearPresence marks the client away in every group it announced itself in,
+// unless the same user is still connected on another socket here.
+func (client *Client) clearPresence() {
+	client.mutex.Lock()
+	groups := make([]uuid.UUID, 0, len(client.presence))
+	for groupID := range client.presence {
+		groups = append(groups, groupID)
+	}
+	client.presence = make(map[uuid.UUID]presenceSent)
+	client.mutex.Unlock()
+
+	if len(groups) == 0 || len(client.hub.users.lookup(client.ID)) > 0 {
+		return
+	}
+	for _, groupID := range groups {
+		client.hub.publishPresence(PresenceUpdate{
+			Group: groupID,
+			User:  client.ID,
+			State: PresenceAway,
+		})
+	}
+}
+
+type presenceSent struct {
+	state  PresenceState
+	sentAt time.Time
+}
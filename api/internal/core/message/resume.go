@@ -0,0 +1,153 @@
+package message
+
+import (
+	database "api/internal/core/db"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// maxReplayEvents caps how much of a group's log one resume replays. Clients
+// that missed more are told to refetch instead.
+const maxReplayEvents = 500
+
+// resume replays the events the client missed in each group, then releases
+// the live messages that arrived meanwhile. Each group ends with a status:
+// "resume.complete" means every missed event up to Sequence was sent, and
+// "resume.reset" means the log no longer covers the gap and the client should
+// refetch the group. It returns false if the client could not keep up and
+// should be disconnected.
+func (client *Client) resume(sequences map[uuid.UUID]int64) bool {
+	client.mutex.Lock()
+	client.replaying = true
+	client.mutex.Unlock()
+
+	replayed := make(map[uuid.UUID]int64, len(sequences))
+	connected := true
+	for groupID, after := range sequences {
+		if !client.subscribed(groupID) {
+			continue
+		}
+		last, ok := client.replayGroup(groupID, after)
+		if !ok {
+			connected = false
+			break
+		}
+		replayed[groupID] = last
+	}
+
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	client.replaying = false
+	held := client.held
+	client.held = nil
+	if !connected || client.overflow {
+		return false
+	}
+	for _, msg := range held {
+		// Skip live events the replay already covered.
+		if msg.Group != nil && msg.Sequence <= replayed[*msg.Group] {
+			continue
+		}
+		select {
+		case <-client.done:
+			return false
+		case client.send <- msg.Data:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// replayGroup sends the retained events after the given sequence and returns
+// the last sequence sent. It returns false if the client stopped reading.
+func (client *Client) replayGroup(groupID uuid.UUID, after int64) (int64, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	current, sequenceErr := client.hub.queries.GetGroupEventSequence(ctx, groupID)
+	if errors.Is(sequenceErr, pgx.ErrNoRows) {
+		current, sequenceErr = 0, nil
+	}
+	if sequenceErr != nil {
+		log.Printf("Failed to fetch event sequence for group %s: %v", groupID, sequenceErr)
+		return after, client.sendResumeStatus("resume.reset", groupID, current)
+	}
+	if after > current {
+		return after, client.sendResumeStatus("resume.reset", groupID, current)
+	}
+	if after == current {
+		return current, client.sendResumeStatus("resume.complete", groupID, current)
+	}
+
+	listEvents := database.ListGroupEventsAfterParams{
+		GroupID:  groupID,
+		Sequence: after,
+		Limit:    maxReplayEvents + 1,
+	}
+	events, listErr := client.hub.queries.ListGroupEventsAfter(ctx, listEvents)
+	if listErr != nil {
+		log.Printf("Failed to fetch events for group %s: %v", groupID, listErr)
+		return after, client.sendResumeStatus("resume.reset", groupID, current)
+	}
+	// Events before the first one retained have been pruned.
+	if len(events) == 0 || len(events) > maxReplayEvents || events[0].Sequence != after+1 {
+		return after, client.sendResumeStatus("resume.reset", groupID, current)
+	}
+
+	blockers, blockersErr := client.blockers(ctx)
+	if blockersErr != nil {
+		log.Printf("Failed to fetch blockers of %s: %v", client.ID, blockersErr)
+		return after, client.sendResumeStatus("resume.reset", groupID, current)
+	}
+
+	last := after
+	for _, event := range events {
+		// Posts by users who blocked the client are skipped, as they are live.
+		if author := eventAuthor(event.Payload); author != nil && blockers[*author] {
+			last = event.Sequence
+			continue
+		}
+		if !client.sendBlocking(event.Payload) {
+			return last, false
+		}
+		last = event.Sequence
+	}
+	return last, client.sendResumeStatus("resume.complete", groupID, last)
+}
+
+// blockers returns the users who have blocked the client's user.
+func (client *Client) blockers(ctx context.Context) (map[uuid.UUID]bool, error) {
+	blockerIDs, err := client.hub.queries.ListBlockerIDs(ctx, client.ID)
+	if err != nil {
+		return nil, err
+	}
+	blockers := make(map[uuid.UUID]bool, len(blockerIDs))
+	for _, blockerID := range blockerIDs {
+		blockers[blockerID] = true
+	}
+	return blockers, nil
+}
+
+// eventAuthor reads the author of a retained post event, if it is one.
+func eventAuthor(payload []byte) *uuid.UUID {
+	var envelope Envelope
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return nil
+	}
+	return author(envelope)
+}
+
+func (client *Client) sendResumeStatus(statusType string, groupID uuid.UUID, sequence int64) bool {
+	return client.sendStatus(statusMessage{
+		Type:     statusType,
+		Group:    groupID,
+		Sequence: sequence,
+	})
+}
@@ -0,0 +1,208 @@
+package message
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PropertyType is the JSON type of a property in an event's data. UUID and
+// Timestamp are strings that must also parse.
+type PropertyType string
+
+const (
+	PropertyString    PropertyType = "string"
+	PropertyUUID      PropertyType = "uuid"
+	PropertyTimestamp PropertyType = "timestamp"
+)
+
+// Schema describes the data of one event type: the latest version and the
+// properties it carries. Optional properties may be missing or null.
+type Schema struct {
+	Version    int
+	Properties map[string]PropertyType
+	Required   []string
+}
+
+var postSchema = Schema{
+	Version: 1,
+	Properties: map[string]PropertyType{
+		"id":          PropertyUUID,
+		"userId":      PropertyUUID,
+		"media":       PropertyString,
+		"dateCreated": PropertyTimestamp,
+		"caption":     PropertyString,
+		"status":      PropertyString,
+	},
+	Required: []string{"id", "userId", "media", "status"},
+}
+
+var postRefSchema = Schema{
+	Version: 1,
+	Properties: map[string]PropertyType{
+		"postId": PropertyUUID,
+	},
+	Required: []string{"postId"},
+}
+
+var memberSchema = Schema{
+	Version: 1,
+	Properties: map[string]PropertyType{
+		"groupId":  PropertyUUID,
+		"userId":   PropertyUUID,
+		"joinedAt": PropertyTimestamp,
+		"role":     PropertyString,
+	},
+	Required: []string{"groupId", "userId", "role"},
+}
+
+// memberRefSchema names a member who left or was removed from a group.
+var memberRefSchema = Schema{
+	Version: 1,
+	Properties: map[string]PropertyType{
+		"groupId": PropertyUUID,
+		"userId":  PropertyUUID,
+	},
+	Required: []string{"groupId", "userId"},
+}
+
+var groupRefSchema = Schema{
+	Version: 1,
+	Properties: map[string]PropertyType{
+		"groupId": PropertyUUID,
+	},
+	Required: []string{"groupId"},
+}
+
+var reactionSchema = Schema{
+	Version: 1,
+	Properties: map[string]PropertyType{
+		"postId":   PropertyUUID,
+		"userId":   PropertyUUID,
+		"reaction": PropertyString,
+	},
+	Required: []string{"postId", "userId", "reaction"},
+}
+
+var commentSchema = Schema{
+	Version: 1,
+	Properties: map[string]PropertyType{
+		"id":          PropertyUUID,
+		"postId":      PropertyUUID,
+		"groupId":     PropertyUUID,
+		"userId":      PropertyUUID,
+		"parentId":    PropertyUUID,
+		"content":     PropertyString,
+		"dateCreated": PropertyTimestamp,
+		"dateUpdated": PropertyTimestamp,
+	},
+	Required: []string{"id", "postId", "groupId", "userId", "content"},
+}
+
+var directMessageSchema = Schema{
+	Version: 1,
+	Properties: map[string]PropertyType{
+		"id":          PropertyUUID,
+		"senderId":    PropertyUUID,
+		"recipientId": PropertyUUID,
+		"content":     PropertyString,
+		"dateCreated": PropertyTimestamp,
+		"dateRead":    PropertyTimestamp,
+	},
+	Required: []string{"id", "senderId", "recipientId", "content"},
+}
+
+var directReadSchema = Schema{
+	Version: 1,
+	Properties: map[string]PropertyType{
+		"readerId": PropertyUUID,
+		"senderId": PropertyUUID,
+		"until":    PropertyTimestamp,
+	},
+	Required: []string{"readerId", "senderId", "until"},
+}
+
+var schemas = map[EventType]Schema{
+	EventPostPublished:   postSchema,
+	EventPostUpdated:     postSchema,
+	EventPostRemoved:     postRefSchema,
+	EventPostDeleted:     postRefSchema,
+	EventMemberJoined:    memberSchema,
+	EventMemberLeft:      memberRefSchema,
+	EventMemberRemoved:   memberRefSchema,
+	EventGroupDeleted:    groupRefSchema,
+	EventReactionAdded:   reactionSchema,
+	EventReactionRemoved: reactionSchema,
+	EventCommentCreated:  commentSchema,
+	EventCommentUpdated:  commentSchema,
+	EventCommentDeleted:  commentSchema,
+	EventDirectMessage:   directMessageSchema,
+	EventDirectRead:      directReadSchema,
+}
+
+// JSONSchemas renders the data schema of every event type as a JSON Schema
+// document so clients can generate or check their own decoders.
+func JSONSchemas() map[EventType]map[string]any {
+	documents := make(map[EventType]map[string]any, len(schemas))
+	for eventType, schema := range schemas {
+		documents[eventType] = schema.jsonSchema(eventType)
+	}
+	return documents
+}
+
+func (schema Schema) jsonSchema(eventType EventType) map[string]any {
+	properties := make(map[string]any, len(schema.Properties))
+	for name, propertyType := range schema.Properties {
+		switch propertyType {
+		case PropertyUUID:
+			properties[name] = map[string]any{"type": []string{"string", "null"}, "format": "uuid"}
+		case PropertyTimestamp:
+			properties[name] = map[string]any{"type": []string{"string", "null"}, "format": "date-time"}
+		default:
+			properties[name] = map[string]any{"type": []string{"string", "null"}}
+		}
+	}
+	return map[string]any{
+		"$schema":    "https://json-schema.org/draft/2020-12/schema",
+		"title":      string(eventType),
+		"version":    schema.Version,
+		"type":       "object",
+		"properties": properties,
+		"required":   schema.Required,
+	}
+}
+
+func (schema Schema) validate(data json.RawMessage) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("data is not an object: %w", err)
+	}
+	for _, name := range schema.Required {
+		if value, ok := fields[name]; !ok || string(value) == "null" {
+			return fmt.Errorf("missing %s", name)
+		}
+	}
+	for name, propertyType := range schema.Properties {
+		value, ok := fields[name]
+		if !ok || string(value) == "null" {
+			continue
+		}
+		var text string
+		if err := json.Unmarshal(value, &text); err != nil {
+			return fmt.Errorf("%s must be a string", name)
+		}
+		switch propertyType {
+		case PropertyUUID:
+			if _, err := uuid.Parse(text); err != nil {
+				return fmt.Errorf("%s is not a uuid", name)
+			}
+		case PropertyTimestamp:
+			if _, err := time.Parse(time.RFC3339Nano, text); err != nil {
+				return fmt.Errorf("%s is not a timestamp", name)
+			}
+		}
+	}
+	return nil
+}
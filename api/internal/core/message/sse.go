@@ -0,0 +1,106 @@
+package message
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ServeSSE streams a group's events as Server-Sent Events for clients that
+// can't hold a websocket. Each event is the same envelope the websocket gets,
+// with its group sequence as the event ID. When lastEventID is set the events
+// after it are replayed first, as with a websocket resume.
+func ServeSSE(hub *Hub, writer http.ResponseWriter, request *http.Request, clientID uuid.UUID, groupID uuid.UUID, lastEventID *int64) {
+	flusher, ok := writer.(http.Flusher)
+	if !ok {
+		http.Error(writer, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	client := &Client{
+		hub:       hub,
+		send:      make(chan []byte, 256),
+		ID:        clientID,
+		groupOnly: true,
+		done:      make(chan struct{}),
+		groups:    map[uuid.UUID]bool{groupID: true},
+		presence:  make(map[uuid.UUID]presenceSent),
+		// Hold live events from the moment the client is registered until
+		// the replay has caught up.
+		replaying: lastEventID != nil,
+	}
+	hub.register(client)
+	defer func() {
+		hub.unregister(client)
+		client.close()
+	}()
+
+	header := writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	// Stop nginx from buffering the stream.
+	header.Set("X-Accel-Buffering", "no")
+	writer.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	if lastEventID != nil {
+		go func() {
+			if !client.resume(map[uuid.UUID]int64{groupID: *lastEventID}) {
+				client.close()
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(pingPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-request.Context().Done():
+			return
+		case <-client.done:
+			return
+		case payload := <-client.send:
+			if err := writeSSEEvent(writer, payload); err != nil {
+				log.Println("SSE write error for client", client.ID, ":", err)
+				return
+			}
+			flusher.Flush()
+		case <-ticker.C:
+			// Comments keep proxies from timing out an idle stream.
+			if _, err := io.WriteString(writer, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeSSEEvent frames one payload. Envelopes and resume statuses carry a
+// group sequence, which becomes the event ID the browser sends back as
+// Last-Event-ID.
+func writeSSEEvent(writer io.Writer, payload []byte) error {
+	var header struct {
+		Sequence int64 `json:"sequence"`
+	}
+	json.Unmarshal(payload, &header)
+
+	var frame bytes.Buffer
+	if header.Sequence > 0 {
+		fmt.Fprintf(&frame, "id: %d\n", header.Sequence)
+	}
+	for _, line := range bytes.Split(payload, []byte("\n")) {
+		frame.WriteString("data: ")
+		frame.Write(line)
+		frame.WriteString("\n")
+	}
+	frame.WriteString("\n")
+	_, err := writer.Write(frame.Bytes())
+	return err
+}
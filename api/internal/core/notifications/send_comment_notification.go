@@ -0,0 +1,45 @@
+package notifications
+
+import (
+	database "api/internal/core/db"
+	"context"
+	"time"
+
+	"firebase.google.com/go/v4/messaging"
+)
+
+// SendCommentNotification tells a post's author that someone commented on it.
+func SendCommentNotification(
+	queries *database.Queries,
+	post *database.Post,
+	comment *database.Comment,
+	user *database.User,
+	messagingClient *messaging.Client,
+) {
+	if post.UserID == user.ID {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	author, err := queries.GetUser(ctx, post.UserID)
+	if err != nil {
+		cancel()
+		return
+	}
+	for _, token := range author.DeviceTokens {
+		notification := Notification{
+			Token: token,
+			Title: user.Username + " commented on your post",
+			Body:  comment.Content,
+			Data: map[string]string{
+				"postId":    post.ID.String(),
+				"groupId":   comment.GroupID.String(),
+				"commentId": comment.ID.String(),
+			},
+		}
+		go func() {
+			SendNotification(&notification, messagingClient)
+		}()
+	}
+}
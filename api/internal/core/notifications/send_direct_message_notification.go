@@ -0,0 +1,40 @@
+package notifications
+
+import (
+	database "api/internal/core/db"
+	"context"
+	"time"
+
+	"firebase.google.com/go/v4/messaging"
+)
+
+// SendDirectMessageNotification pushes a direct message to the recipient's
+// devices. It is only used when the recipient has no socket open.
+func SendDirectMessageNotification(
+	queries *database.Queries,
+	directMessage *database.DirectMessage,
+	sender *database.User,
+	messagingClient *messaging.Client,
+) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	recipient, err := queries.GetUser(ctx, directMessage.RecipientID)
+	if err != nil {
+		return
+	}
+	for _, token := range recipient.DeviceTokens {
+		notification := Notification{
+			Token: token,
+			Title: sender.Username,
+			Body:  directMessage.Content,
+			Data: map[string]string{
+				"senderId":  sender.ID.String(),
+				"messageId": directMessage.ID.String(),
+			},
+		}
+		go func() {
+			SendNotification(&notification, messagingClient)
+		}()
+	}
+}
@@ -0,0 +1,122 @@
+package producer
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+)
+
+// Broker delivers encoded events to a topic.
+type Broker interface {
+	Send(topic string, key string, value []byte) error
+	Close() error
+}
+
+// sendTimeout bounds how long Send waits for room in the producer's input
+// while Kafka is slow or unreachable.
+const sendTimeout = 5 * time.Second
+
+var ErrSendTimeout = errors.New("timed out queueing kafka message")
+
+// KafkaBroker writes events with an async producer so publishing doesn't wait
+// for Kafka to acknowledge them. Delivery failures are drained from Errors
+// and logged, since they happen after Send has returned.
+type KafkaBroker struct {
+	producer sarama.AsyncProducer
+}
+
+func NewKafkaBroker(brokers []string) (*KafkaBroker, error) {
+	config := sarama.NewConfig()
+	config.Producer.RequiredAcks = sarama.WaitForLocal
+	config.Producer.Partitioner = sarama.NewHashPartitioner
+	config.Producer.Return.Errors = true
+
+	producer, err := sarama.NewAsyncProducer(brokers, config)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		for err := range producer.Errors() {
+			log.Printf("Failed to deliver kafka message %v: %v", err.Msg.Key, err.Err)
+		}
+	}()
+	return &KafkaBroker{producer: producer}, nil
+}
+
+// Send queues the message, or returns ErrSendTimeout if the producer can't
+// take it within sendTimeout.
+func (broker *KafkaBroker) Send(topic string, key string, value []byte) error {
+	timer := time.NewTimer(sendTimeout)
+	defer timer.Stop()
+
+	select {
+	case broker.producer.Input() <- &sarama.ProducerMessage{
+		Topic: topic,
+		Key:   sarama.StringEncoder(key),
+		Value: sarama.ByteEncoder(value),
+	}:
+		return nil
+	case <-timer.C:
+		return ErrSendTimeout
+	}
+}
+
+func (broker *KafkaBroker) Close() error {
+	return broker.producer.Close()
+}
+
+// Message is an event as recorded by MemoryBroker.
+type Message struct {
+	Topic string
+	Key   string
+	Value []byte
+}
+
+// MemoryBroker keeps every message in memory and hands it to its subscribers
+// synchronously. It stands in for Kafka in tests; the server falls back to
+// PostgresBroker instead, which reaches every replica.
+type MemoryBroker struct {
+	mutex       sync.Mutex
+	messages    []Message
+	subscribers []func(Message)
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{}
+}
+
+// Subscribe registers handler to be called with every message sent after it.
+func (broker *MemoryBroker) Subscribe(handler func(Message)) {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+	broker.subscribers = append(broker.subscribers, handler)
+}
+
+func (broker *MemoryBroker) Send(topic string, key string, value []byte) error {
+	msg := Message{Topic: topic, Key: key, Value: value}
+	broker.mutex.Lock()
+	broker.messages = append(broker.messages, msg)
+	subscribers := broker.subscribers
+	broker.mutex.Unlock()
+
+	for _, subscriber := range subscribers {
+		subscriber(msg)
+	}
+	return nil
+}
+
+// Messages returns a copy of everything sent so far.
+func (broker *MemoryBroker) Messages() []Message {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+	messages := make([]Message, len(broker.messages))
+	copy(messages, broker.messages)
+	return messages
+}
+
+func (broker *MemoryBroker) Close() error {
+	return nil
+}
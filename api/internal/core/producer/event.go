@@ -0,0 +1,69 @@
+package producer
+
+import (
+	"api/internal/core/message"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event is a domain event before it is wrapped in a message.Envelope.
+// Exactly one of Group or User is set, and it decides the message key.
+type Event struct {
+	Type  message.EventType
+	Group *uuid.UUID
+	User  *uuid.UUID
+	Data  any
+}
+
+// PostRef is the data of events that only need to name the post.
+type PostRef struct {
+	PostID uuid.UUID `json:"postId"`
+}
+
+// MemberRef is the data of events about a member leaving a group.
+type MemberRef struct {
+	GroupID uuid.UUID `json:"groupId"`
+	UserID  uuid.UUID `json:"userId"`
+}
+
+// GroupRef is the data of events that only need to name the group.
+type GroupRef struct {
+	GroupID uuid.UUID `json:"groupId"`
+}
+
+// Reaction is the data of reaction events.
+type Reaction struct {
+	PostID   uuid.UUID `json:"postId"`
+	UserID   uuid.UUID `json:"userId"`
+	Reaction string    `json:"reaction"`
+}
+
+// DirectRead is the data of read receipts: ReaderID has read every message
+// SenderID sent them up to and including Until.
+type DirectRead struct {
+	ReaderID uuid.UUID `json:"readerId"`
+	SenderID uuid.UUID `json:"senderId"`
+	Until    time.Time `json:"until"`
+}
+
+func groupFromKey(key string) (uuid.UUID, bool) {
+	return idFromKey(key, "group")
+}
+
+func userFromKey(key string) (uuid.UUID, bool) {
+	return idFromKey(key, "user")
+}
+
+func idFromKey(key string, subject string) (uuid.UUID, bool) {
+	keySubject, subjectID, found := strings.Cut(key, ":")
+	if !found || keySubject != subject {
+		return uuid.Nil, false
+	}
+	groupID, err := uuid.Parse(subjectID)
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return groupID, true
+}
@@ -0,0 +1,52 @@
+package producer
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/message"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// PostgresBroker publishes with NOTIFY on the realtime_events channel, which
+// every replica listens on. It stands in for Kafka when no broker is
+// reachable. Large group events are sent as a pointer into group_events,
+// which the Store must already have retained.
+type PostgresBroker struct {
+	queries *database.Queries
+}
+
+func NewPostgresBroker(queries *database.Queries) *PostgresBroker {
+	return &PostgresBroker{queries: queries}
+}
+
+func (broker *PostgresBroker) Send(topic string, key string, value []byte) error {
+	notification := message.Notification{Key: key, Value: value}
+	if len(value) > message.MaxNotificationValue {
+		var envelope message.Envelope
+		if err := json.Unmarshal(value, &envelope); err != nil {
+			return err
+		}
+		if envelope.Group == nil {
+			return fmt.Errorf("event %s is too large to notify", envelope.ID)
+		}
+		notification = message.Notification{
+			Key:      key,
+			Group:    envelope.Group,
+			Sequence: envelope.Sequence,
+		}
+	}
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	notifyContext, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return broker.queries.NotifyRealtimeEvent(notifyContext, string(payload))
+}
+
+func (broker *PostgresBroker) Close() error {
+	return nil
+}
@@ -0,0 +1,163 @@
+package producer
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/message"
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Publisher wraps domain events in envelopes and sends them to the posts
+// topic, where the websocket consumer picks them up. The typed helpers log
+// failures instead of returning them since an event is never worth failing a
+// request over.
+type Publisher struct {
+	broker Broker
+	store  Store
+	topic  string
+}
+
+func NewPublisher(broker Broker, store Store) *Publisher {
+	return &Publisher{
+		broker: broker,
+		store:  store,
+		topic:  message.POST_TOPIC,
+	}
+}
+
+// Publish validates a single event and sends it keyed by its group or user.
+// The store hands out sequences atomically, so concurrent publishes don't
+// wait on each other. Events of one key may then reach the broker out of
+// order, and clients order them by sequence.
+func (publisher *Publisher) Publish(event Event) error {
+	envelope, err := message.NewEnvelope(event.Type, event.Data)
+	if err != nil {
+		return err
+	}
+	envelope.Group = event.Group
+	envelope.User = event.User
+
+	storeContext, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	envelope.Sequence, err = publisher.store.Next(storeContext, envelope.Key())
+	if err != nil {
+		return err
+	}
+	if err := envelope.Validate(); err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+	// Connected clients still get the event if it can't be retained, only a
+	// later replay will be missing it.
+	if err := publisher.store.Append(storeContext, envelope, payload); err != nil {
+		log.Printf("Failed to retain %s event %s: %v", envelope.Type, envelope.ID, err)
+	}
+	return publisher.broker.Send(publisher.topic, envelope.Key(), payload)
+}
+
+func (publisher *Publisher) Close() error {
+	return publisher.broker.Close()
+}
+
+func (publisher *Publisher) PostPublished(post database.Post, groupIDs []uuid.UUID) {
+	publisher.publishToGroups(message.EventPostPublished, groupIDs, post)
+}
+
+func (publisher *Publisher) PostUpdated(post database.Post, groupIDs []uuid.UUID) {
+	publisher.publishToGroups(message.EventPostUpdated, groupIDs, post)
+}
+
+// PostRemoved tells groups a post was taken out of them, while it may still
+// live on in others.
+func (publisher *Publisher) PostRemoved(postID uuid.UUID, groupIDs []uuid.UUID) {
+	publisher.publishToGroups(message.EventPostRemoved, groupIDs, PostRef{PostID: postID})
+}
+
+func (publisher *Publisher) PostDeleted(postID uuid.UUID, groupIDs []uuid.UUID) {
+	publisher.publishToGroups(message.EventPostDeleted, groupIDs, PostRef{PostID: postID})
+}
+
+// MemberJoined is sent to the group and to the new member, whose open sockets
+// are not subscribed to the group yet.
+func (publisher *Publisher) MemberJoined(member database.FriendGroupMember) {
+	publisher.publishToGroups(message.EventMemberJoined, []uuid.UUID{member.GroupID}, member)
+	publisher.publishToUser(message.EventMemberJoined, member.UserID, member)
+}
+
+// MemberLeft tells the group a member left. Every replica drops the member's
+// subscriptions to the group once it has delivered the event.
+func (publisher *Publisher) MemberLeft(groupID uuid.UUID, userID uuid.UUID) {
+	publisher.publishToGroups(message.EventMemberLeft, []uuid.UUID{groupID}, MemberRef{GroupID: groupID, UserID: userID})
+}
+
+// MemberRemoved is also sent to the removed member so all of their devices
+// learn about it, including those not following the group.
+func (publisher *Publisher) MemberRemoved(groupID uuid.UUID, userID uuid.UUID) {
+	ref := MemberRef{GroupID: groupID, UserID: userID}
+	publisher.publishToGroups(message.EventMemberRemoved, []uuid.UUID{groupID}, ref)
+	publisher.publishToUser(message.EventMemberRemoved, userID, ref)
+}
+
+// GroupDeleted is sent to each former member rather than to the group, since
+// the group's event log is deleted along with it.
+func (publisher *Publisher) GroupDeleted(groupID uuid.UUID, memberIDs []uuid.UUID) {
+	for _, memberID := range memberIDs {
+		publisher.publishToUser(message.EventGroupDeleted, memberID, GroupRef{GroupID: groupID})
+	}
+}
+
+func (publisher *Publisher) ReactionAdded(reaction Reaction, groupIDs []uuid.UUID) {
+	publisher.publishToGroups(message.EventReactionAdded, groupIDs, reaction)
+}
+
+func (publisher *Publisher) ReactionRemoved(reaction Reaction, groupIDs []uuid.UUID) {
+	publisher.publishToGroups(message.EventReactionRemoved, groupIDs, reaction)
+}
+
+func (publisher *Publisher) CommentCreated(comment database.Comment) {
+	publisher.publishToGroups(message.EventCommentCreated, []uuid.UUID{comment.GroupID}, comment)
+}
+
+func (publisher *Publisher) CommentUpdated(comment database.Comment) {
+	publisher.publishToGroups(message.EventCommentUpdated, []uuid.UUID{comment.GroupID}, comment)
+}
+
+func (publisher *Publisher) CommentDeleted(comment database.Comment) {
+	publisher.publishToGroups(message.EventCommentDeleted, []uuid.UUID{comment.GroupID}, comment)
+}
+
+// DirectMessageCreated goes to both users so the sender's other devices see
+// the message too.
+func (publisher *Publisher) DirectMessageCreated(directMessage database.DirectMessage) {
+	publisher.publishToUser(message.EventDirectMessage, directMessage.RecipientID, directMessage)
+	publisher.publishToUser(message.EventDirectMessage, directMessage.SenderID, directMessage)
+}
+
+func (publisher *Publisher) DirectMessagesRead(read DirectRead) {
+	publisher.publishToUser(message.EventDirectRead, read.SenderID, read)
+	publisher.publishToUser(message.EventDirectRead, read.ReaderID, read)
+}
+
+func (publisher *Publisher) publishToGroups(eventType message.EventType, groupIDs []uuid.UUID, data any) {
+	for _, groupID := range groupIDs {
+		event := Event{Type: eventType, Group: &groupID, Data: data}
+		if err := publisher.Publish(event); err != nil {
+			log.Printf("Failed to publish %s event for group %s: %v", eventType, groupID, err)
+		}
+	}
+}
+
+func (publisher *Publisher) publishToUser(eventType message.EventType, userID uuid.UUID, data any) {
+	event := Event{Type: eventType, User: &userID, Data: data}
+	if err := publisher.Publish(event); err != nil {
+		log.Printf("Failed to publish %s event for user %s: %v", eventType, userID, err)
+	}
+}
@@ -0,0 +1,162 @@
+package producer
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/message"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func newTestPublisher() (*Publisher, *MemoryBroker) {
+	broker := NewMemoryBroker()
+	return NewPublisher(broker, NewMemoryStore()), broker
+}
+
+// parse reads a sent message back the way the websocket consumer does.
+func parse(t *testing.T, msg Message) (message.Envelope, *message.WSMessage) {
+	t.Helper()
+	if msg.Topic != message.POST_TOPIC {
+		t.Errorf("message sent to %q, want %q", msg.Topic, message.POST_TOPIC)
+	}
+	wsMessage, err := message.ParseMessage(msg.Key, msg.Value)
+	if err != nil {
+		t.Fatalf("ParseMessage(%q) = %v", msg.Key, err)
+	}
+	var envelope message.Envelope
+	if err := json.Unmarshal(msg.Value, &envelope); err != nil {
+		t.Fatal(err)
+	}
+	return envelope, wsMessage
+}
+
+func TestPublishSequencesPerKey(t *testing.T) {
+	publisher, broker := newTestPublisher()
+	groupA, groupB, user := uuid.New(), uuid.New(), uuid.New()
+	ref := PostRef{PostID: uuid.New()}
+
+	events := []struct {
+		event        Event
+		wantKey      string
+		wantSequence int64
+	}{
+		{event: Event{Type: message.EventPostDeleted, Group: &groupA, Data: ref}, wantKey: "group:" + groupA.String(), wantSequence: 1},
+		{event: Event{Type: message.EventPostDeleted, Group: &groupA, Data: ref}, wantKey: "group:" + groupA.String(), wantSequence: 2},
+		{event: Event{Type: message.EventPostDeleted, Group: &groupB, Data: ref}, wantKey: "group:" + groupB.String(), wantSequence: 1},
+		{event: Event{Type: message.EventGroupDeleted, User: &user, Data: GroupRef{GroupID: groupA}}, wantKey: "user:" + user.String(), wantSequence: 1},
+		{event: Event{Type: message.EventPostDeleted, Group: &groupA, Data: ref}, wantKey: "group:" + groupA.String(), wantSequence: 3},
+	}
+	for _, test := range events {
+		if err := publisher.Publish(test.event); err != nil {
+			t.Fatalf("Publish(%s) = %v", test.event.Type, err)
+		}
+	}
+
+	messages := broker.Messages()
+	if len(messages) != len(events) {
+		t.Fatalf("sent %d messages, want %d", len(messages), len(events))
+	}
+	for i, test := range events {
+		if messages[i].Key != test.wantKey {
+			t.Errorf("message %d has key %q, want %q", i, messages[i].Key, test.wantKey)
+		}
+		envelope, wsMessage := parse(t, messages[i])
+		if envelope.Type != test.event.Type || envelope.Sequence != test.wantSequence || wsMessage.Sequence != test.wantSequence {
+			t.Errorf("message %d is %s #%d, want %s #%d", i, envelope.Type, envelope.Sequence, test.event.Type, test.wantSequence)
+		}
+	}
+}
+
+func TestPublishRejectsInvalidEvents(t *testing.T) {
+	publisher, broker := newTestPublisher()
+	groupID, userID := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name  string
+		event Event
+	}{
+		{name: "unknown type", event: Event{Type: "post.liked", Group: &groupID, Data: PostRef{}}},
+		{name: "no recipient", event: Event{Type: message.EventPostDeleted, Data: PostRef{PostID: uuid.New()}}},
+		{name: "two recipients", event: Event{Type: message.EventPostDeleted, Group: &groupID, User: &userID, Data: PostRef{PostID: uuid.New()}}},
+		{name: "data missing a field", event: Event{Type: message.EventMemberLeft, Group: &groupID, Data: GroupRef{GroupID: groupID}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := publisher.Publish(test.event); err == nil {
+				t.Error("Publish() accepted an invalid event")
+			}
+		})
+	}
+	if messages := broker.Messages(); len(messages) != 0 {
+		t.Errorf("invalid events sent %d messages", len(messages))
+	}
+}
+
+func TestPublisherHelpersAddressEvents(t *testing.T) {
+	groupA, groupB := uuid.New(), uuid.New()
+	userA, userB := uuid.New(), uuid.New()
+	post := database.Post{ID: uuid.New(), UserID: userA, Media: database.MediaTypeTEXT, Status: database.PostStatusPUBLISHED}
+
+	tests := []struct {
+		name     string
+		publish  func(*Publisher)
+		wantType message.EventType
+		wantKeys []string
+	}{
+		{
+			name:     "post published to each group",
+			publish:  func(p *Publisher) { p.PostPublished(post, []uuid.UUID{groupA, groupB}) },
+			wantType: message.EventPostPublished,
+			wantKeys: []string{"group:" + groupA.String(), "group:" + groupB.String()},
+		},
+		{
+			name:     "member left tells the group",
+			publish:  func(p *Publisher) { p.MemberLeft(groupA, userA) },
+			wantType: message.EventMemberLeft,
+			wantKeys: []string{"group:" + groupA.String()},
+		},
+		{
+			name:     "member removed tells the group and the member",
+			publish:  func(p *Publisher) { p.MemberRemoved(groupA, userA) },
+			wantType: message.EventMemberRemoved,
+			wantKeys: []string{"group:" + groupA.String(), "user:" + userA.String()},
+		},
+		{
+			name:     "group deleted tells each member",
+			publish:  func(p *Publisher) { p.GroupDeleted(groupA, []uuid.UUID{userA, userB}) },
+			wantType: message.EventGroupDeleted,
+			wantKeys: []string{"user:" + userA.String(), "user:" + userB.String()},
+		},
+		{
+			name: "reaction added to each group",
+			publish: func(p *Publisher) {
+				p.ReactionAdded(Reaction{PostID: post.ID, UserID: userB, Reaction: "🔥"}, []uuid.UUID{groupB})
+			},
+			wantType: message.EventReactionAdded,
+			wantKeys: []string{"group:" + groupB.String()},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			publisher, broker := newTestPublisher()
+			var received []Message
+			broker.Subscribe(func(msg Message) { received = append(received, msg) })
+
+			test.publish(publisher)
+
+			messages := broker.Messages()
+			if len(messages) != len(test.wantKeys) || len(received) != len(test.wantKeys) {
+				t.Fatalf("sent %d and delivered %d messages, want %d", len(messages), len(received), len(test.wantKeys))
+			}
+			for i, msg := range messages {
+				if msg.Key != test.wantKeys[i] {
+					t.Errorf("message %d has key %q, want %q", i, msg.Key, test.wantKeys[i])
+				}
+				if envelope, _ := parse(t, msg); envelope.Type != test.wantType {
+					t.Errorf("message %d is %s, want %s", i, envelope.Type, test.wantType)
+				}
+			}
+		})
+	}
+}
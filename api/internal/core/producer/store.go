@@ -0,0 +1,110 @@
+package producer
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/message"
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Store numbers events per message key and retains the group events that
+// reconnecting clients may ask to replay.
+type Store interface {
+	Next(ctx context.Context, key string) (int64, error)
+	Append(ctx context.Context, envelope message.Envelope, payload []byte) error
+}
+
+// MemoryStore counts in process and retains nothing. Sequences restart with
+// the process and are not shared between replicas, so it is only meant for
+// tests.
+type MemoryStore struct {
+	mutex sync.Mutex
+	last  map[string]int64
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{last: make(map[string]int64)}
+}
+
+func (store *MemoryStore) Next(ctx context.Context, key string) (int64, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.last[key]++
+	return store.last[key], nil
+}
+
+func (store *MemoryStore) Append(ctx context.Context, envelope message.Envelope, payload []byte) error {
+	return nil
+}
+
+// PostgresStore gives every group and every user one sequence shared by all
+// replicas, and keeps group events in group_events for retention. Events
+// addressed to a single user are not retained.
+type PostgresStore struct {
+	queries   *database.Queries
+	retention time.Duration
+}
+
+func NewPostgresStore(queries *database.Queries, retention time.Duration) *PostgresStore {
+	return &PostgresStore{
+		queries:   queries,
+		retention: retention,
+	}
+}
+
+func (store *PostgresStore) Next(ctx context.Context, key string) (int64, error) {
+	if groupID, isGroup := groupFromKey(key); isGroup {
+		return store.queries.NextGroupEventSequence(ctx, groupID)
+	}
+	if userID, isUser := userFromKey(key); isUser {
+		return store.queries.NextUserEventSequence(ctx, userID)
+	}
+	return 0, fmt.Errorf("unknown event key %q", key)
+}
+
+func (store *PostgresStore) Append(ctx context.Context, envelope message.Envelope, payload []byte) error {
+	if envelope.Group == nil {
+		return nil
+	}
+	groupEvent := database.CreateGroupEventParams{
+		GroupID:     *envelope.Group,
+		Sequence:    envelope.Sequence,
+		ID:          envelope.ID,
+		Payload:     payload,
+		DateCreated: pgtype.Timestamptz{Time: envelope.Timestamp, Valid: true},
+	}
+	return store.queries.CreateGroupEvent(ctx, groupEvent)
+}
+
+// Start prunes events older than the retention every interval until ctx is
+// cancelled. It returns immediately.
+func (store *PostgresStore) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		store.prune(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				store.prune(ctx)
+			}
+		}
+	}()
+}
+
+func (store *PostgresStore) prune(ctx context.Context) {
+	pruneContext, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	cutoff := pgtype.Timestamptz{Time: time.Now().Add(-store.retention), Valid: true}
+	if err := store.queries.DeleteGroupEventsBefore(pruneContext, cutoff); err != nil {
+		log.Printf("Failed to prune group events: %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package score
+
+import (
+	database "api/internal/core/db"
+	"log"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Weights configures how much each signal contributes to a post's score.
+type Weights struct {
+	Engagement float64
+	Freshness  float64
+	Velocity   float64
+
+	// Per-interaction weights used when summing engagement.
+	Reaction float64
+	Comment  float64
+	View     float64
+
+	// DecayRate is the exponential freshness decay applied per hour of age.
+	DecayRate float64
+	// VelocityWindow is how far back an interaction still counts as recent.
+	VelocityWindow time.Duration
+	// DiversityPenalty is applied once for every higher ranked post by the
+	// same author, so one person can't flood the top of a group.
+	DiversityPenalty float64
+}
+
+// Signals are the raw inputs the ranking model needs for a single post.
+type Signals struct {
+	Post               database.Post
+	Reactions          int64
+	Comments           int64
+	Views              int64
+	RecentInteractions int64
+}
+
+func DefaultWeights() Weights {
+	return Weights{
+		Engagement:       0.5,
+		Freshness:        0.3,
+		Velocity:         0.2,
+		Reaction:         3,
+		Comment:          4,
+		View:             1,
+		DecayRate:        0.1,
+		VelocityWindow:   time.Hour,
+		DiversityPenalty: 0.8,
+	}
+}
+
+// WeightsFromEnv starts from DefaultWeights and overrides any value set in the
+// SCORE_* environment variables.
+func WeightsFromEnv() Weights {
+	weights := DefaultWeights()
+	envFloat("SCORE_WEIGHT_ENGAGEMENT", &weights.Engagement)
+	envFloat("SCORE_WEIGHT_FRESHNESS", &weights.Freshness)
+	envFloat("SCORE_WEIGHT_VELOCITY", &weights.Velocity)
+	envFloat("SCORE_WEIGHT_REACTION", &weights.Reaction)
+	envFloat("SCORE_WEIGHT_COMMENT", &weights.Comment)
+	envFloat("SCORE_WEIGHT_VIEW", &weights.View)
+	envFloat("SCORE_DECAY_RATE", &weights.DecayRate)
+	envFloat("SCORE_DIVERSITY_PENALTY", &weights.DiversityPenalty)
+	if value := os.Getenv("SCORE_VELOCITY_WINDOW"); value != "" {
+		window, err := time.ParseDuration(value)
+		if err != nil || window <= 0 {
+			log.Printf("Ignoring invalid SCORE_VELOCITY_WINDOW %q", value)
+		} else {
+			weights.VelocityWindow = window
+		}
+	}
+	return weights
+}
+
+func envFloat(key string, target *float64) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Printf("Ignoring invalid %s %q: %v", key, value, err)
+		return
+	}
+	*target = parsed
+}
+
+// baseScore is the score of a post on its own, before the diversity penalty.
+func (weights Weights) baseScore(signals Signals, now time.Time) float64 {
+	engagement := math.Log1p(
+		float64(signals.Reactions)*weights.Reaction +
+			float64(signals.Comments)*weights.Comment +
+			float64(signals.Views)*weights.View,
+	)
+	freshness := math.Exp(-weights.DecayRate * now.Sub(signals.Post.DateCreated.Time).Hours())
+	velocity := 0.0
+	if hours := weights.VelocityWindow.Hours(); hours > 0 {
+		velocity = math.Log1p(float64(signals.RecentInteractions) / hours)
+	}
+
+	return engagement*weights.Engagement + freshness*weights.Freshness + velocity*weights.Velocity
+}
+
+// Rank scores every post in a group. Posts are ranked by their base score and
+// each author's later posts are multiplied by DiversityPenalty once per
+// earlier post from them.
+func (weights Weights) Rank(posts []Signals, now time.Time) map[uuid.UUID]float64 {
+	type ranked struct {
+		post  database.Post
+		score float64
+	}
+	ranking := make([]ranked, 0, len(posts))
+	for _, signals := range posts {
+		ranking = append(ranking, ranked{
+			post:  signals.Post,
+			score: weights.baseScore(signals, now),
+		})
+	}
+	sort.SliceStable(ranking, func(i, j int) bool {
+		if ranking[i].score != ranking[j].score {
+			return ranking[i].score > ranking[j].score
+		}
+		return ranking[i].post.ID.String() > ranking[j].post.ID.String()
+	})
+
+	scores := make(map[uuid.UUID]float64, len(ranking))
+	authorPosts := make(map[uuid.UUID]int)
+	for _, entry := range ranking {
+		penalty := math.Pow(weights.DiversityPenalty, float64(authorPosts[entry.post.UserID]))
+		authorPosts[entry.post.UserID]++
+		scores[entry.post.ID] = entry.score * penalty
+	}
+	return scores
+}
@@ -0,0 +1,91 @@
+package score
+
+import (
+	database "api/internal/core/db"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestRank(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	alice, bob := uuid.New(), uuid.New()
+	post := func(author uuid.UUID, age time.Duration) database.Post {
+		return database.Post{
+			ID:          uuid.New(),
+			UserID:      author,
+			DateCreated: pgtype.Timestamptz{Time: now.Add(-age), Valid: true},
+		}
+	}
+	weights := DefaultWeights()
+
+	t.Run("engagement ranks higher", func(t *testing.T) {
+		quiet := Signals{Post: post(alice, time.Hour)}
+		busy := Signals{Post: post(bob, time.Hour), Reactions: 5, Comments: 2}
+		scores := weights.Rank([]Signals{quiet, busy}, now)
+		if scores[busy.Post.ID] <= scores[quiet.Post.ID] {
+			t.Errorf("busy post scored %v, not above quiet post's %v", scores[busy.Post.ID], scores[quiet.Post.ID])
+		}
+	})
+
+	t.Run("fresher ranks higher", func(t *testing.T) {
+		old := Signals{Post: post(alice, 48*time.Hour)}
+		fresh := Signals{Post: post(bob, time.Minute)}
+		scores := weights.Rank([]Signals{old, fresh}, now)
+		if scores[fresh.Post.ID] <= scores[old.Post.ID] {
+			t.Errorf("fresh post scored %v, not above old post's %v", scores[fresh.Post.ID], scores[old.Post.ID])
+		}
+	})
+
+	t.Run("velocity ranks higher", func(t *testing.T) {
+		steady := Signals{Post: post(alice, time.Hour), Reactions: 4}
+		trending := Signals{Post: post(bob, time.Hour), Reactions: 4, RecentInteractions: 4}
+		scores := weights.Rank([]Signals{steady, trending}, now)
+		if scores[trending.Post.ID] <= scores[steady.Post.ID] {
+			t.Errorf("trending post scored %v, not above steady post's %v", scores[trending.Post.ID], scores[steady.Post.ID])
+		}
+	})
+
+	t.Run("diversity penalty per earlier post by the author", func(t *testing.T) {
+		first := Signals{Post: post(alice, time.Hour), Reactions: 3}
+		second := Signals{Post: post(alice, time.Hour), Reactions: 2}
+		third := Signals{Post: post(alice, time.Hour), Reactions: 1}
+		other := Signals{Post: post(bob, time.Hour), Reactions: 1}
+		signals := []Signals{third, other, first, second}
+
+		unpenalized := weights
+		unpenalized.DiversityPenalty = 1
+		base := unpenalized.Rank(signals, now)
+		scores := weights.Rank(signals, now)
+
+		tests := []struct {
+			name    string
+			post    database.Post
+			penalty float64
+		}{
+			{name: "first", post: first.Post, penalty: 1},
+			{name: "second", post: second.Post, penalty: weights.DiversityPenalty},
+			{name: "third", post: third.Post, penalty: weights.DiversityPenalty * weights.DiversityPenalty},
+			{name: "other author", post: other.Post, penalty: 1},
+		}
+		for _, test := range tests {
+			want := base[test.post.ID] * test.penalty
+			if got := scores[test.post.ID]; math.Abs(got-want) > 1e-12 {
+				t.Errorf("%s post scored %v, want %v", test.name, got, want)
+			}
+		}
+	})
+
+	t.Run("every post is scored", func(t *testing.T) {
+		signals := []Signals{{Post: post(alice, 0)}, {Post: post(alice, 0)}, {Post: post(bob, 0)}}
+		if scores := weights.Rank(signals, now); len(scores) != len(signals) {
+			t.Errorf("Rank() scored %d posts, want %d", len(scores), len(signals))
+		}
+		if scores := weights.Rank(nil, now); len(scores) != 0 {
+			t.Errorf("Rank(nil) = %v, want no scores", scores)
+		}
+	})
+}
@@ -0,0 +1,174 @@
+package score
+
+import (
+	database "api/internal/core/db"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Scheduler recalculates group scores on a fixed interval using a bounded pool
+// of workers. Periodic runs take a lease on the group in score_leases so that
+// only one API replica scores a group in each window.
+type Scheduler struct {
+	queries  *database.Queries
+	interval time.Duration
+	workers  int
+	// holder identifies this replica in score_leases.
+	holder string
+
+	jobs chan *scoreJob
+
+	// pending deduplicates groups that are already queued.
+	mutex   sync.Mutex
+	pending map[uuid.UUID]*scoreJob
+}
+
+type scoreJob struct {
+	groupID uuid.UUID
+	// leased jobs come from the ticker and must win the group's lease first.
+	// Triggered jobs run straight away so new posts get ranked immediately.
+	leased bool
+	// triggered is set when a Trigger was merged into the job, so the run
+	// is retried if the lease is lost or the calculation fails.
+	triggered bool
+	// retry marks the one extra run a failed trigger gets.
+	retry bool
+}
+
+func NewScheduler(queries *database.Queries, interval time.Duration, workers int) *Scheduler {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "api"
+	}
+	return &Scheduler{
+		queries:  queries,
+		interval: interval,
+		workers:  workers,
+		holder:   fmt.Sprintf("%s-%s", hostname, uuid.NewString()),
+		jobs:     make(chan *scoreJob, 1024),
+		pending:  make(map[uuid.UUID]*scoreJob),
+	}
+}
+
+// Start launches the worker pool and the ticker. It returns immediately; all
+// goroutines stop when ctx is cancelled.
+func (scheduler *Scheduler) Start(ctx context.Context) {
+	for range scheduler.workers {
+		go scheduler.work(ctx)
+	}
+	go func() {
+		ticker := time.NewTicker(scheduler.interval)
+		defer ticker.Stop()
+
+		scheduler.scheduleAll(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				scheduler.scheduleAll(ctx)
+			}
+		}
+	}()
+}
+
+// Trigger asks for a group to be rescored as soon as a worker is free. Calls
+// for a group that is already queued are collapsed into the queued run.
+func (scheduler *Scheduler) Trigger(groupID uuid.UUID) {
+	scheduler.enqueue(&scoreJob{groupID: groupID, triggered: true})
+}
+
+// scheduleAll queues every group. Groups are listed on each tick so groups
+// created after startup are picked up.
+func (scheduler *Scheduler) scheduleAll(ctx context.Context) {
+	listContext, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	friendGroups, err := scheduler.queries.ListFriendGroups(listContext)
+	if err != nil {
+		log.Printf("Failed to fetch friend groups for calculation job: %v", err)
+		return
+	}
+	for _, friendGroup := range friendGroups {
+		scheduler.enqueue(&scoreJob{groupID: friendGroup.ID, leased: true})
+	}
+}
+
+func (scheduler *Scheduler) enqueue(job *scoreJob) {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	if queued := scheduler.pending[job.groupID]; queued != nil {
+		// The queued run picks up the trigger, and retries it if it fails.
+		queued.triggered = queued.triggered || job.triggered
+		return
+	}
+
+	select {
+	case scheduler.jobs <- job:
+		scheduler.pending[job.groupID] = job
+	default:
+		// The next tick will pick the group up again.
+		log.Printf("Score queue full, dropping calculation for group %s", job.groupID)
+	}
+}
+
+func (scheduler *Scheduler) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-scheduler.jobs:
+			// Clear pending before running so a trigger that arrives mid-run
+			// queues another pass that sees the new post.
+			scheduler.mutex.Lock()
+			delete(scheduler.pending, job.groupID)
+			triggered := job.triggered
+			scheduler.mutex.Unlock()
+
+			if job.leased && !scheduler.acquireLease(ctx, job.groupID) {
+				// A trigger merged into this run still needs its rescore,
+				// so it goes back on the queue without the lease.
+				if triggered {
+					scheduler.enqueue(&scoreJob{groupID: job.groupID, triggered: true})
+				}
+				continue
+			}
+			err := RunScoreCalculation(job.groupID, scheduler.queries)
+			if err != nil && triggered && !job.retry {
+				scheduler.enqueue(&scoreJob{groupID: job.groupID, triggered: true, retry: true})
+			}
+		}
+	}
+}
+
+// acquireLease claims the group until the end of the current window. Windows
+// are aligned to the interval so every replica agrees on their boundaries.
+func (scheduler *Scheduler) acquireLease(ctx context.Context, groupID uuid.UUID) bool {
+	leaseContext, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	windowEnd := time.Now().Truncate(scheduler.interval).Add(scheduler.interval)
+	lease := database.AcquireScoreLeaseParams{
+		GroupID:   groupID,
+		Holder:    scheduler.holder,
+		ExpiresAt: pgtype.Timestamptz{Time: windowEnd, Valid: true},
+	}
+	_, err := scheduler.queries.AcquireScoreLease(leaseContext, lease)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Another replica already scored this group in the current window.
+		return false
+	}
+	if err != nil {
+		log.Printf("Failed to acquire score lease for group %s: %v", groupID, err)
+		return false
+	}
+	return true
+}
@@ -4,7 +4,6 @@ import (
 	database "api/internal/core/db"
 	"context"
 	"log"
-	"math"
 	"strconv"
 	"time"
 
@@ -12,59 +11,58 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-// calculateScore computes the score based on the given post.
-func calcluateScore(post database.Post) float64 {
-	freshnessScore := math.Exp(-0.1 * time.Since(post.DateCreated.Time).Hours())
+// weights is the ranking configuration, read from the environment at startup.
+var weights = WeightsFromEnv()
 
-	//score := (engagementScore*0.5 + freshnessScore*0.3 + interactionVelocity*0.2) * diversityPenalty
-	return freshnessScore
-}
-
-func RoutineScoreCalculator(groupId uuid.UUID, queries *database.Queries) {
-	ticker := time.NewTicker(1 * time.Minute)
-	defer ticker.Stop()
-
-	// Run immediately if needed:
-	log.Printf("Running initial score calc %s", groupId)
-	RunScoreCalculation(groupId, queries)
-	for {
-		select {
-		case <-ticker.C:
-			RunScoreCalculation(groupId, queries)
-		}
-	}
-}
-
-func RunScoreCalculation(groupId uuid.UUID, queries *database.Queries) {
+// RunScoreCalculation rescores every published post in a group. Failures are
+// logged and returned so the scheduler can retry triggered runs.
+func RunScoreCalculation(groupId uuid.UUID, queries *database.Queries) error {
 	//@TODO: Make an actual context for this bitch
 	ctx, cancel := context.WithTimeout(context.Background(), 5 * time.Second)
 	defer cancel()
-	posts, err := queries.ListPostsForGroup(ctx, groupId)
+	now := time.Now()
+	signalParams := database.ListPostSignalsForGroupParams{
+		Since:   pgtype.Timestamptz{Time: now.Add(-weights.VelocityWindow), Valid: true},
+		GroupID: groupId,
+	}
+	rows, err := queries.ListPostSignalsForGroup(ctx, signalParams)
 	if err != nil {
 		log.Printf("Failed to fetch posts for calculation job")
 		cancel()
-		return
+		return err
+	}
+	posts := make([]Signals, 0, len(rows))
+	for _, row := range rows {
+		posts = append(posts, Signals{
+			Post:               row.Post,
+			Reactions:          row.Reactions,
+			Comments:           row.Comments,
+			Views:              row.Views,
+			RecentInteractions: row.RecentViews + row.RecentReactions + row.RecentComments,
+		})
 	}
-	for _, post := range posts {
-		score := calcluateScore(post.Post)
+	for postID, score := range weights.Rank(posts, now) {
 		var numeric pgtype.Numeric
-		log.Printf("Running initial score calc %s", groupId)
 		numeric, err := Float64ToPgNumeric(score)
 		if err != nil {
 			log.Printf("Failed to convert score to pgtype.Numeric: %v", err)
 			cancel()
-			return
+			return err
 		}
+		// Each group ranks its posts separately, so only this group's row
+		// is updated.
 		updateScore := database.UpdatePostScoreParams{
-			PostID: post.Post.ID,
-			Score:  numeric,
+			PostID:  postID,
+			Score:   numeric,
+			GroupID: groupId,
 		}
 		if err := queries.UpdatePostScore(ctx, updateScore); err != nil {
 			log.Printf("Failed to update post score: %v", err)
 			cancel()
-			return
+			return err
 		}
 	}
+	return nil
 }
 
 func Float64ToPgNumeric(f float64) (pgtype.Numeric, error) {
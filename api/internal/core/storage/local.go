@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// LocalStorage keeps media on disk, for development and tests without a
+// bucket. The router serves the directory under Path.
+type LocalStorage struct {
+	root      string
+	publicURL string
+}
+
+func NewLocalStorage(root string, publicURL string) (*LocalStorage, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalStorage{root: root, publicURL: publicURL}, nil
+}
+
+// Dir is the directory media is written to.
+func (storage *LocalStorage) Dir() string {
+	return storage.root
+}
+
+// Path is the URL path the directory has to be served under.
+func (storage *LocalStorage) Path() string {
+	parsed, err := url.Parse(storage.publicURL)
+	if err != nil || parsed.Path == "" {
+		return "/media"
+	}
+	return parsed.Path
+}
+
+// Put writes to a temporary file first so readers never see half a file. The
+// content type is implied by the key's extension when the file is served.
+func (storage *LocalStorage) Put(ctx context.Context, key string, body io.Reader, contentType string) error {
+	path, err := storage.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err := io.Copy(temp, body); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), path)
+}
+
+func (storage *LocalStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := storage.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+// Stat guesses the content type from the extension, the same way the file
+// is served.
+func (storage *LocalStorage) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	path, err := storage.path(key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ObjectInfo{}, ErrNotFound
+	}
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return ObjectInfo{
+		Size:        info.Size(),
+		ContentType: mime.TypeByExtension(filepath.Ext(path)),
+	}, nil
+}
+
+func (storage *LocalStorage) Delete(ctx context.Context, keys []string) error {
+	for _, key := range keys {
+		path, err := storage.path(key)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (storage *LocalStorage) URL(key string) string {
+	return joinURL(storage.publicURL, key)
+}
+
+func (storage *LocalStorage) Key(mediaURL string) (string, bool) {
+	return keyFromURL(storage.publicURL, mediaURL)
+}
+
+// path resolves key inside the root, refusing keys that would escape it.
+func (storage *LocalStorage) path(key string) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(key)) {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+	return filepath.Join(storage.root, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// MinIOConfig points the S3 client at an S3 compatible server such as MinIO.
+type MinIOConfig struct {
+	Endpoint  string
+	Region    string
+	AccessKey string
+	SecretKey string
+	Bucket    string
+	PublicURL string
+}
+
+// NewMinIOStorage returns an S3Storage that uses static credentials and path
+// style addressing, which MinIO needs unless it is set up for virtual hosts.
+func NewMinIOStorage(ctx context.Context, minio MinIOConfig) (*S3Storage, error) {
+	if minio.Bucket == "" {
+		return nil, errors.New("BUCKET_NAME is required for the minio backend")
+	}
+	region := minio.Region
+	if region == "" {
+		// MinIO ignores the region but the signer still needs one.
+		region = "us-east-1"
+	}
+	cfg, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(region),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(minio.AccessKey, minio.SecretKey, "")),
+	)
+	if err != nil {
+		return nil, err
+	}
+	client := s3.NewFromConfig(cfg, func(options *s3.Options) {
+		options.BaseEndpoint = aws.String(minio.Endpoint)
+		options.UsePathStyle = true
+	})
+	return &S3Storage{
+		client:    client,
+		bucket:    minio.Bucket,
+		publicURL: minio.PublicURL,
+	}, nil
+}
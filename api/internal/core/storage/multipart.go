@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// MultipartUploader is implemented by backends that can assemble an object
+// from parts uploaded separately, so a broken connection only costs the part
+// that was in flight.
+type MultipartUploader interface {
+	CreateMultipartUpload(ctx context.Context, key string, contentType string) (string, error)
+	// PresignUploadPart returns a request that uploads exactly size bytes as
+	// part number of the upload. Part numbers start at 1.
+	PresignUploadPart(ctx context.Context, key string, uploadID string, number int32, size int64, expires time.Duration) (PresignedRequest, error)
+	// ListParts returns the parts uploaded so far, in order.
+	ListParts(ctx context.Context, key string, uploadID string) ([]Part, error)
+	CompleteMultipartUpload(ctx context.Context, key string, uploadID string, parts []Part) error
+	AbortMultipartUpload(ctx context.Context, key string, uploadID string) error
+}
+
+// Part is one uploaded part of a multipart upload.
+type Part struct {
+	Number int32  `json:"number"`
+	Size   int64  `json:"size"`
+	ETag   string `json:"-"`
+}
+
+// ErrNoSuchUpload is returned when a multipart upload was already completed
+// or aborted.
+var ErrNoSuchUpload = errors.New("multipart upload not found")
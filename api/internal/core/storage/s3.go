@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3Storage keeps media in an S3 bucket. The client is built once and shared
+// by every request.
+type S3Storage struct {
+	client    *s3.Client
+	bucket    string
+	publicURL string
+}
+
+// NewS3Storage loads the default AWS config from the environment.
+func NewS3Storage(ctx context.Context, bucket string, publicURL string) (*S3Storage, error) {
+	if bucket == "" {
+		return nil, errors.New("BUCKET_NAME is required for the s3 backend")
+	}
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &S3Storage{
+		client:    s3.NewFromConfig(cfg),
+		bucket:    bucket,
+		publicURL: publicURL,
+	}, nil
+}
+
+// Put uploads body in a single request. Bodies that can't seek, and so can't
+// be hashed up front, need a TLS endpoint.
+func (storage *S3Storage) Put(ctx context.Context, key string, body io.Reader, contentType string) error {
+	_, err := storage.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(storage.bucket),
+		Key:         aws.String(key),
+		Body:        body,
+		ContentType: aws.String(contentType),
+	})
+	return err
+}
+
+func (storage *S3Storage) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	out, err := storage.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(storage.bucket),
+		Key:    aws.String(key),
+	})
+	var notFound *types.NotFound
+	if errors.As(err, &notFound) {
+		return ObjectInfo{}, ErrNotFound
+	}
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return ObjectInfo{
+		Size:        aws.ToInt64(out.ContentLength),
+		ContentType: aws.ToString(out.ContentType),
+	}, nil
+}
+
+// PresignPut signs the content type and length, so S3 rejects an upload of
+// anything else.
+func (storage *S3Storage) PresignPut(ctx context.Context, key string, contentType string, size int64, expires time.Duration) (PresignedRequest, error) {
+	presigner := s3.NewPresignClient(storage.client)
+	request, err := presigner.PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(storage.bucket),
+		Key:           aws.String(key),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(size),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return PresignedRequest{}, err
+	}
+	return presignedRequest(request), nil
+}
+
+func presignedRequest(request *v4.PresignedHTTPRequest) PresignedRequest {
+	headers := make(map[string]string, len(request.SignedHeader))
+	for name, values := range request.SignedHeader {
+		// The HTTP client sets Host itself.
+		if len(values) == 0 || strings.EqualFold(name, "Host") {
+			continue
+		}
+		headers[name] = values[0]
+	}
+	return PresignedRequest{
+		URL:     request.URL,
+		Method:  request.Method,
+		Headers: headers,
+	}
+}
+
+func (storage *S3Storage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := storage.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(storage.bucket),
+		Key:    aws.String(key),
+	})
+	var noSuchKey *types.NoSuchKey
+	if errors.As(err, &noSuchKey) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+// Delete removes every key in a single request.
+func (storage *S3Storage) Delete(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	objects := make([]types.ObjectIdentifier, 0, len(keys))
+	for _, key := range keys {
+		objects = append(objects, types.ObjectIdentifier{Key: aws.String(key)})
+	}
+	out, err := storage.client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+		Bucket: aws.String(storage.bucket),
+		Delete: &types.Delete{
+			Objects: objects,
+			Quiet:   aws.Bool(true),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if len(out.Errors) > 0 {
+		return fmt.Errorf("failed to delete %d of %d objects: %s", len(out.Errors), len(keys), aws.ToString(out.Errors[0].Message))
+	}
+	return nil
+}
+
+func (storage *S3Storage) URL(key string) string {
+	return joinURL(storage.publicURL, key)
+}
+
+func (storage *S3Storage) Key(mediaURL string) (string, bool) {
+	return keyFromURL(storage.publicURL, mediaURL)
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func (storage *S3Storage) CreateMultipartUpload(ctx context.Context, key string, contentType string) (string, error) {
+	out, err := storage.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(storage.bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(out.UploadId), nil
+}
+
+func (storage *S3Storage) PresignUploadPart(ctx context.Context, key string, uploadID string, number int32, size int64, expires time.Duration) (PresignedRequest, error) {
+	presigner := s3.NewPresignClient(storage.client)
+	request, err := presigner.PresignUploadPart(ctx, &s3.UploadPartInput{
+		Bucket:        aws.String(storage.bucket),
+		Key:           aws.String(key),
+		UploadId:      aws.String(uploadID),
+		PartNumber:    aws.Int32(number),
+		ContentLength: aws.Int64(size),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return PresignedRequest{}, err
+	}
+	return presignedRequest(request), nil
+}
+
+func (storage *S3Storage) ListParts(ctx context.Context, key string, uploadID string) ([]Part, error) {
+	var parts []Part
+	paginator := s3.NewListPartsPaginator(storage.client, &s3.ListPartsInput{
+		Bucket:   aws.String(storage.bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, multipartError(err)
+		}
+		for _, part := range page.Parts {
+			parts = append(parts, Part{
+				Number: aws.ToInt32(part.PartNumber),
+				Size:   aws.ToInt64(part.Size),
+				ETag:   aws.ToString(part.ETag),
+			})
+		}
+	}
+	return parts, nil
+}
+
+func (storage *S3Storage) CompleteMultipartUpload(ctx context.Context, key string, uploadID string, parts []Part) error {
+	completed := make([]types.CompletedPart, 0, len(parts))
+	for _, part := range parts {
+		completed = append(completed, types.CompletedPart{
+			ETag:       aws.String(part.ETag),
+			PartNumber: aws.Int32(part.Number),
+		})
+	}
+	_, err := storage.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(storage.bucket),
+		Key:             aws.String(key),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+	})
+	return multipartError(err)
+}
+
+func (storage *S3Storage) AbortMultipartUpload(ctx context.Context, key string, uploadID string) error {
+	_, err := storage.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(storage.bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	return multipartError(err)
+}
+
+func multipartError(err error) error {
+	var noSuchUpload *types.NoSuchUpload
+	if errors.As(err, &noSuchUpload) {
+		return ErrNoSuchUpload
+	}
+	return err
+}
@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Storage holds uploaded media. Keys are slash separated paths such as
+// images/<id>/full.jpeg, and URL is where clients fetch a key from.
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Stat(ctx context.Context, key string) (ObjectInfo, error)
+	// Delete removes every key. Keys that don't exist are not an error.
+	Delete(ctx context.Context, keys []string) error
+	URL(key string) string
+	// Key maps a URL returned by URL back to its key.
+	Key(mediaURL string) (string, bool)
+}
+
+// Presigner is implemented by backends that clients can upload to directly,
+// without the bytes passing through the API.
+type Presigner interface {
+	// PresignPut returns a request that uploads exactly size bytes of
+	// contentType to key until it expires.
+	PresignPut(ctx context.Context, key string, contentType string, size int64, expires time.Duration) (PresignedRequest, error)
+}
+
+// PresignedRequest is what the client has to send. Headers are part of the
+// signature and must be sent unchanged.
+type PresignedRequest struct {
+	URL     string            `json:"url"`
+	Method  string            `json:"method"`
+	Headers map[string]string `json:"headers"`
+}
+
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+}
+
+// ErrNotFound is returned by Get and Stat when the key doesn't exist.
+var ErrNotFound = errors.New("object not found")
+
+// FromEnv builds the backend named by STORAGE_BACKEND:
+//
+//   - s3 (default) uses the AWS default config, BUCKET_NAME and serves
+//     through CLOUDFRONT_DOMAIN.
+//   - minio talks to any S3 compatible server at STORAGE_ENDPOINT with
+//     STORAGE_ACCESS_KEY and STORAGE_SECRET_KEY.
+//   - local writes to the STORAGE_LOCAL_ROOT directory.
+//
+// STORAGE_PUBLIC_URL overrides the base URL media is served from.
+func FromEnv(ctx context.Context) (Storage, error) {
+	publicURL := os.Getenv("STORAGE_PUBLIC_URL")
+	backend := os.Getenv("STORAGE_BACKEND")
+	switch backend {
+	case "", "s3":
+		if publicURL == "" {
+			publicURL = "https://" + os.Getenv("CLOUDFRONT_DOMAIN")
+		}
+		return NewS3Storage(ctx, os.Getenv("BUCKET_NAME"), publicURL)
+	case "minio":
+		endpoint := os.Getenv("STORAGE_ENDPOINT")
+		if endpoint == "" {
+			return nil, errors.New("STORAGE_ENDPOINT is required for the minio backend")
+		}
+		bucket := os.Getenv("BUCKET_NAME")
+		if publicURL == "" {
+			publicURL = strings.TrimSuffix(endpoint, "/") + "/" + bucket
+		}
+		return NewMinIOStorage(ctx, MinIOConfig{
+			Endpoint:  endpoint,
+			Region:    os.Getenv("STORAGE_REGION"),
+			AccessKey: os.Getenv("STORAGE_ACCESS_KEY"),
+			SecretKey: os.Getenv("STORAGE_SECRET_KEY"),
+			Bucket:    bucket,
+			PublicURL: publicURL,
+		})
+	case "local":
+		root := os.Getenv("STORAGE_LOCAL_ROOT")
+		if root == "" {
+			root = "media"
+		}
+		if publicURL == "" {
+			publicURL = "/media"
+		}
+		return NewLocalStorage(root, publicURL)
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_BACKEND %q", backend)
+	}
+}
+
+func joinURL(publicURL string, key string) string {
+	return strings.TrimSuffix(publicURL, "/") + "/" + key
+}
+
+// keyFromURL strips the public URL off mediaURL. Only the path is compared
+// so media stored before a domain change still resolves.
+func keyFromURL(publicURL string, mediaURL string) (string, bool) {
+	base, err := url.Parse(publicURL)
+	if err != nil {
+		return "", false
+	}
+	parsed, err := url.Parse(mediaURL)
+	if err != nil {
+		return "", false
+	}
+	prefix := strings.TrimSuffix(base.Path, "/") + "/"
+	if !strings.HasPrefix(parsed.Path, prefix) {
+		return "", false
+	}
+	key := strings.TrimPrefix(parsed.Path, prefix)
+	return key, key != ""
+}
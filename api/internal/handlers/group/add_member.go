@@ -2,12 +2,15 @@ package handlers
 
 import (
 	database "api/internal/core/db"
+	"api/internal/core/producer"
 	"api/internal/core/utils"
 	"api/internal/middleware"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 )
 
 type AddMemberRequest struct {
@@ -15,7 +18,7 @@ type AddMemberRequest struct {
 	GroupId  uuid.UUID `json:"groupId"`
 }
 
-func AddMemberHandler(queries *database.Queries) gin.HandlerFunc {
+func AddMemberHandler(queries *database.Queries, publisher *producer.Publisher) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		token, tokenErr := middleware.GetAuthToken(ctx)
 		if tokenErr != nil {
@@ -38,11 +41,20 @@ func AddMemberHandler(queries *database.Queries) gin.HandlerFunc {
 			return
 		}
 
-		getFriendship := database.GetFriendshipParams{
+		if _, ok := requireMember(ctx, queries, addRequest.GroupId, user.ID); !ok {
+			return
+		}
+
+		getFriendship := database.GetFriendshipBetweenParams{
 			UserID:   user.ID,
 			FriendID: addRequest.FriendId,
 		}
-		friendship, friendErr := queries.GetFriendship(ctx.Request.Context(), getFriendship)
+		friendship, friendErr := queries.GetFriendshipBetween(ctx.Request.Context(), getFriendship)
+		if errors.Is(friendErr, pgx.ErrNoRows) {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
 		if friendErr != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get friendship status"})
 			gin.DefaultWriter.Write([]byte("Failed to get friendship status" + friendErr.Error()))
@@ -55,6 +67,23 @@ func AddMemberHandler(queries *database.Queries) gin.HandlerFunc {
 			return
 		}
 
+		// Nobody in the group may have blocked the friend, or been blocked by them.
+		checkBlocked := database.CheckBlockedInGroupParams{
+			UserID:  addRequest.FriendId,
+			GroupID: addRequest.GroupId,
+		}
+		isBlocked, blockedErr := queries.CheckBlockedInGroup(ctx.Request.Context(), checkBlocked)
+		if blockedErr != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check blocks"})
+			gin.DefaultWriter.Write([]byte("Failed to check blocks: " + blockedErr.Error()))
+			return
+		}
+		if isBlocked {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized access: User is blocked in the group"))
+			return
+		}
+
 		addMember := database.AddUserToGroupParams{
 			GroupID:  addRequest.GroupId,
 			UserID:   addRequest.FriendId,
@@ -66,7 +95,9 @@ func AddMemberHandler(queries *database.Queries) gin.HandlerFunc {
 		if addErr != nil {
 			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add to group"})
 			gin.DefaultWriter.Write([]byte("Failed to add to group: " + addErr.Error()))
+			return
 		}
+		publisher.MemberJoined(friendGroup)
 
 		ctx.JSON(http.StatusOK, friendGroup)
 	}
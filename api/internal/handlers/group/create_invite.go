@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/invite"
+	"api/internal/core/utils"
+	"api/internal/middleware"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	defaultInviteHours = 7 * 24
+	maxInviteHours     = 30 * 24
+	maxInviteUses      = 1000
+)
+
+type CreateInviteRequest struct {
+	GroupId uuid.UUID `json:"groupId" binding:"required"`
+	// ExpiresInHours defaults to a week. MaxUses is unlimited when unset.
+	ExpiresInHours *int   `json:"expiresInHours"`
+	MaxUses        *int32 `json:"maxUses"`
+}
+
+// CreateInviteHandler lets any member of a group create a shareable invite
+// code for it.
+func CreateInviteHandler(queries *database.Queries) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		var createRequest CreateInviteRequest
+		if bindErr := ctx.Bind(&createRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+
+		expiresInHours := defaultInviteHours
+		if createRequest.ExpiresInHours != nil {
+			expiresInHours = *createRequest.ExpiresInHours
+		}
+		if expiresInHours <= 0 || expiresInHours > maxInviteHours {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expiry"})
+			gin.DefaultWriter.Write([]byte("Invalid expiry"))
+			return
+		}
+		if createRequest.MaxUses != nil && (*createRequest.MaxUses <= 0 || *createRequest.MaxUses > maxInviteUses) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid max uses"})
+			gin.DefaultWriter.Write([]byte("Invalid max uses"))
+			return
+		}
+
+		if _, ok := requireMember(ctx, queries, createRequest.GroupId, user.ID); !ok {
+			return
+		}
+
+		code, codeErr := invite.NewCode()
+		if codeErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to generate invite code: "+codeErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to generate invite code: " + codeErr.Error()))
+			return
+		}
+		now := utils.TwoCentsTime()
+		createInvite := database.CreateGroupInviteParams{
+			Code:        code,
+			GroupID:     createRequest.GroupId,
+			CreatedBy:   user.ID,
+			DateCreated: pgtype.Timestamptz{Time: now, Valid: true},
+			ExpiresAt:   pgtype.Timestamptz{Time: now.Add(time.Duration(expiresInHours) * time.Hour), Valid: true},
+			MaxUses:     createRequest.MaxUses,
+		}
+		groupInvite, createErr := queries.CreateGroupInvite(ctx.Request.Context(), createInvite)
+		if createErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to create invite: "+createErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to create invite: " + createErr.Error()))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, groupInvite)
+	}
+}
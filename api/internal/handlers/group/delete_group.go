@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/producer"
+	"api/internal/middleware"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DeleteGroupRequest struct {
+	GroupId uuid.UUID `json:"groupId" binding:"required"`
+}
+
+// DeleteGroupHandler lets the owner delete a group. Posts shared to it are
+// kept, only their link to the group is removed. Every former member is sent
+// a group.deleted event, which also ends their subscriptions to it.
+func DeleteGroupHandler(queries *database.Queries, publisher *producer.Publisher) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		var deleteRequest DeleteGroupRequest
+		if bindErr := ctx.Bind(&deleteRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+
+		if _, ok := requireOwner(ctx, queries, deleteRequest.GroupId, user.ID); !ok {
+			return
+		}
+
+		// Members have to be listed before the group and its memberships go.
+		members, membersErr := queries.ListGroupMembers(ctx.Request.Context(), deleteRequest.GroupId)
+		if membersErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch members: "+membersErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch members: " + membersErr.Error()))
+			return
+		}
+
+		if err := queries.DeleteFriendGroup(ctx.Request.Context(), deleteRequest.GroupId); err != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to delete group: "+err.Error())
+			gin.DefaultWriter.Write([]byte("Failed to delete group: " + err.Error()))
+			return
+		}
+		memberIDs := make([]uuid.UUID, 0, len(members))
+		for _, member := range members {
+			memberIDs = append(memberIDs, member.UserID)
+		}
+		publisher.GroupDeleted(deleteRequest.GroupId, memberIDs)
+
+		ctx.JSON(http.StatusOK, gin.H{"success": "Deleted group"})
+	}
+}
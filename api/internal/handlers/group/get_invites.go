@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/middleware"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetInvitesHandler lists a group's invites that haven't been revoked so
+// admins can see what is out there.
+func GetInvitesHandler(queries *database.Queries) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		groupID, parseErr := uuid.Parse(ctx.Query("groupId"))
+		if parseErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid groupId"})
+			gin.DefaultWriter.Write([]byte("Invalid groupId: " + parseErr.Error()))
+			return
+		}
+
+		if _, ok := requireAdmin(ctx, queries, groupID, user.ID); !ok {
+			return
+		}
+
+		invites, listErr := queries.ListGroupInvites(ctx.Request.Context(), groupID)
+		if listErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch invites: "+listErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch invites: " + listErr.Error()))
+			return
+		}
+		if invites == nil {
+			invites = []database.GroupInvite{}
+		}
+
+		ctx.JSON(http.StatusOK, invites)
+	}
+}
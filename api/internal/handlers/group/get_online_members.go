@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/message"
+	"api/internal/middleware"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetOnlineMembersHandler lists the members of a group who currently have it
+// open, with what they are doing. Presence expires within seconds, so the
+// response isn't cached.
+func GetOnlineMembersHandler(queries *database.Queries, hub *message.Hub) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		groupID, parseErr := uuid.Parse(ctx.Query("groupId"))
+		if parseErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid groupId"})
+			gin.DefaultWriter.Write([]byte("Invalid groupId: " + parseErr.Error()))
+			return
+		}
+		if _, ok := requireMember(ctx, queries, groupID, user.ID); !ok {
+			return
+		}
+
+		ctx.JSON(http.StatusOK, hub.Online(groupID))
+	}
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/producer"
+	"api/internal/middleware"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type LeaveGroupRequest struct {
+	GroupId uuid.UUID `json:"groupId" binding:"required"`
+}
+
+// LeaveGroupHandler removes the caller from a group. The owner has to transfer
+// ownership or delete the group instead. Their open connections stop
+// following the group once the member.left event reaches them.
+func LeaveGroupHandler(queries *database.Queries, publisher *producer.Publisher) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		var leaveRequest LeaveGroupRequest
+		if bindErr := ctx.Bind(&leaveRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+
+		if _, ok := requireMember(ctx, queries, leaveRequest.GroupId, user.ID); !ok {
+			return
+		}
+		friendGroup, groupErr := queries.GetFriendGroup(ctx.Request.Context(), leaveRequest.GroupId)
+		if groupErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch group: "+groupErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch group: " + groupErr.Error()))
+			return
+		}
+		if friendGroup.OwnerID == user.ID {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "Transfer ownership or delete the group before leaving"})
+			gin.DefaultWriter.Write([]byte("Owner tried to leave group"))
+			return
+		}
+
+		removeUser := database.RemoveUserFromGroupParams{
+			GroupID: leaveRequest.GroupId,
+			UserID:  user.ID,
+		}
+		if err := queries.RemoveUserFromGroup(ctx.Request.Context(), removeUser); err != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to leave group: "+err.Error())
+			gin.DefaultWriter.Write([]byte("Failed to leave group: " + err.Error()))
+			return
+		}
+		publisher.MemberLeft(removeUser.GroupID, removeUser.UserID)
+
+		ctx.JSON(http.StatusOK, gin.H{"success": "Left group"})
+	}
+}
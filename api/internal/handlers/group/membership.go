@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// requireMember fetches the user's membership in a group. If the user doesn't
+// belong to the group the response has already been written and ok is false.
+func requireMember(ctx *gin.Context, queries *database.Queries, groupID uuid.UUID, userID uuid.UUID) (database.FriendGroupMember, bool) {
+	getMember := database.GetGroupMemberParams{
+		GroupID: groupID,
+		UserID:  userID,
+	}
+	member, memberErr := queries.GetGroupMember(ctx.Request.Context(), getMember)
+	if errors.Is(memberErr, pgx.ErrNoRows) {
+		ctx.String(http.StatusUnauthorized, "Unauthorized")
+		gin.DefaultWriter.Write([]byte("Unauthorized access: User is not a member of the group"))
+		return member, false
+	}
+	if memberErr != nil {
+		ctx.String(http.StatusInternalServerError, "Failed to check membership: "+memberErr.Error())
+		gin.DefaultWriter.Write([]byte("Failed to check membership: " + memberErr.Error()))
+		return member, false
+	}
+	return member, true
+}
+
+// requireAdmin is requireMember for actions reserved to the group's admins.
+func requireAdmin(ctx *gin.Context, queries *database.Queries, groupID uuid.UUID, userID uuid.UUID) (database.FriendGroupMember, bool) {
+	member, ok := requireMember(ctx, queries, groupID, userID)
+	if !ok {
+		return member, false
+	}
+	if member.Role != database.GroupRoleADMIN {
+		ctx.String(http.StatusUnauthorized, "Unauthorized")
+		gin.DefaultWriter.Write([]byte("Unauthorized access: User is not an admin of the group"))
+		return member, false
+	}
+	return member, true
+}
+
+// requireOwner fetches the group and checks the user owns it.
+func requireOwner(ctx *gin.Context, queries *database.Queries, groupID uuid.UUID, userID uuid.UUID) (database.FriendGroup, bool) {
+	friendGroup, groupErr := queries.GetFriendGroup(ctx.Request.Context(), groupID)
+	if errors.Is(groupErr, pgx.ErrNoRows) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Group not found"})
+		gin.DefaultWriter.Write([]byte("Group not found"))
+		return friendGroup, false
+	}
+	if groupErr != nil {
+		ctx.String(http.StatusInternalServerError, "Failed to fetch group: "+groupErr.Error())
+		gin.DefaultWriter.Write([]byte("Failed to fetch group: " + groupErr.Error()))
+		return friendGroup, false
+	}
+	if friendGroup.OwnerID != userID {
+		ctx.String(http.StatusUnauthorized, "Unauthorized")
+		gin.DefaultWriter.Write([]byte("Unauthorized access: User does not own the group"))
+		return friendGroup, false
+	}
+	return friendGroup, true
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/invite"
+	"api/internal/core/utils"
+	"api/internal/middleware"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type InvitePreview struct {
+	Code        string               `json:"code"`
+	Group       database.FriendGroup `json:"group"`
+	MemberCount int                  `json:"memberCount"`
+	ExpiresAt   pgtype.Timestamptz   `json:"expiresAt"`
+	Valid       bool                 `json:"valid"`
+}
+
+// PreviewInviteHandler shows which group an invite code is for. It only needs
+// a Firebase token so it can be shown during onboarding, before the user has
+// registered.
+func PreviewInviteHandler(queries *database.Queries) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if _, tokenErr := middleware.GetAuthToken(ctx); tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+
+		code := invite.NormalizeCode(ctx.Query("code"))
+		if code == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "code is required"})
+			gin.DefaultWriter.Write([]byte("Failed to query code"))
+			return
+		}
+
+		groupInvite, inviteErr := queries.GetGroupInvite(ctx.Request.Context(), code)
+		if errors.Is(inviteErr, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Invite not found"})
+			gin.DefaultWriter.Write([]byte("Invite not found"))
+			return
+		}
+		if inviteErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch invite: "+inviteErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch invite: " + inviteErr.Error()))
+			return
+		}
+
+		friendGroup, groupErr := queries.GetFriendGroup(ctx.Request.Context(), groupInvite.GroupID)
+		if groupErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch group: "+groupErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch group: " + groupErr.Error()))
+			return
+		}
+		members, membersErr := queries.ListGroupMembers(ctx.Request.Context(), groupInvite.GroupID)
+		if membersErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch members: "+membersErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch members: " + membersErr.Error()))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, InvitePreview{
+			Code:        groupInvite.Code,
+			Group:       friendGroup,
+			MemberCount: len(members),
+			ExpiresAt:   groupInvite.ExpiresAt,
+			Valid:       invite.Usable(groupInvite, utils.TwoCentsTime()),
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/invite"
+	"api/internal/core/producer"
+	"api/internal/middleware"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RedeemInviteRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+func RedeemInviteHandler(queries *database.Queries, publisher *producer.Publisher) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		var redeemRequest RedeemInviteRequest
+		if bindErr := ctx.Bind(&redeemRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+
+		member, redeemErr := invite.Redeem(ctx.Request.Context(), queries, publisher, redeemRequest.Code, user.ID)
+		if errors.Is(redeemErr, invite.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Invite not found"})
+			gin.DefaultWriter.Write([]byte("Invite not found"))
+			return
+		}
+		if errors.Is(redeemErr, invite.ErrUnusable) {
+			ctx.JSON(http.StatusGone, gin.H{"error": "Invite is no longer valid"})
+			gin.DefaultWriter.Write([]byte("Invite is no longer valid"))
+			return
+		}
+		if errors.Is(redeemErr, invite.ErrBlocked) {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized access: User is blocked in the group"))
+			return
+		}
+		if redeemErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to redeem invite: "+redeemErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to redeem invite: " + redeemErr.Error()))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, member)
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/producer"
+	"api/internal/middleware"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type RemoveMemberRequest struct {
+	GroupId uuid.UUID `json:"groupId" binding:"required"`
+	UserId  uuid.UUID `json:"userId" binding:"required"`
+}
+
+// RemoveMemberHandler lets an admin kick a member out of a group. Admins can
+// only be removed by the owner, and the owner can't be removed at all. The
+// member's open connections stop following the group once the
+// member.removed event reaches them.
+func RemoveMemberHandler(queries *database.Queries, publisher *producer.Publisher) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		var removeRequest RemoveMemberRequest
+		if bindErr := ctx.Bind(&removeRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+		if removeRequest.UserId == user.ID {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Use leave-group to leave a group"})
+			gin.DefaultWriter.Write([]byte("User tried to remove themselves"))
+			return
+		}
+
+		if _, ok := requireAdmin(ctx, queries, removeRequest.GroupId, user.ID); !ok {
+			return
+		}
+
+		getMember := database.GetGroupMemberParams{
+			GroupID: removeRequest.GroupId,
+			UserID:  removeRequest.UserId,
+		}
+		member, memberErr := queries.GetGroupMember(ctx.Request.Context(), getMember)
+		if errors.Is(memberErr, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+			gin.DefaultWriter.Write([]byte("Member not found"))
+			return
+		}
+		if memberErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch member: "+memberErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch member: " + memberErr.Error()))
+			return
+		}
+
+		friendGroup, groupErr := queries.GetFriendGroup(ctx.Request.Context(), removeRequest.GroupId)
+		if groupErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch group: "+groupErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch group: " + groupErr.Error()))
+			return
+		}
+		if member.UserID == friendGroup.OwnerID || (member.Role == database.GroupRoleADMIN && friendGroup.OwnerID != user.ID) {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized access: Cannot remove this member"))
+			return
+		}
+
+		removeUser := database.RemoveUserFromGroupParams{
+			GroupID: member.GroupID,
+			UserID:  member.UserID,
+		}
+		if err := queries.RemoveUserFromGroup(ctx.Request.Context(), removeUser); err != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to remove member: "+err.Error())
+			gin.DefaultWriter.Write([]byte("Failed to remove member: " + err.Error()))
+			return
+		}
+		publisher.MemberRemoved(removeUser.GroupID, removeUser.UserID)
+
+		ctx.JSON(http.StatusOK, gin.H{"success": "Removed member"})
+	}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/middleware"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const maxGroupNameLength = 64
+
+type RenameGroupRequest struct {
+	GroupId uuid.UUID `json:"groupId" binding:"required"`
+	Name    string    `json:"name" binding:"required"`
+}
+
+func RenameGroupHandler(queries *database.Queries) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		var renameRequest RenameGroupRequest
+		if bindErr := ctx.Bind(&renameRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+		name := strings.TrimSpace(renameRequest.Name)
+		if name == "" || utf8.RuneCountInString(name) > maxGroupNameLength {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group name"})
+			gin.DefaultWriter.Write([]byte("Invalid group name"))
+			return
+		}
+
+		if _, ok := requireAdmin(ctx, queries, renameRequest.GroupId, user.ID); !ok {
+			return
+		}
+
+		updateName := database.UpdateFriendGroupNameParams{
+			ID:   renameRequest.GroupId,
+			Name: name,
+		}
+		friendGroup, updateErr := queries.UpdateFriendGroupName(ctx.Request.Context(), updateName)
+		if updateErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to rename group: "+updateErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to rename group: " + updateErr.Error()))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, friendGroup)
+	}
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/invite"
+	"api/internal/middleware"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type RevokeInviteRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// RevokeInviteHandler lets the invite's creator or a group admin disable an
+// invite. Members who already joined through it are kept.
+func RevokeInviteHandler(queries *database.Queries) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		var revokeRequest RevokeInviteRequest
+		if bindErr := ctx.Bind(&revokeRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+
+		groupInvite, inviteErr := queries.GetGroupInvite(ctx.Request.Context(), invite.NormalizeCode(revokeRequest.Code))
+		if errors.Is(inviteErr, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Invite not found"})
+			gin.DefaultWriter.Write([]byte("Invite not found"))
+			return
+		}
+		if inviteErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch invite: "+inviteErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch invite: " + inviteErr.Error()))
+			return
+		}
+		if groupInvite.CreatedBy != user.ID {
+			if _, ok := requireAdmin(ctx, queries, groupInvite.GroupID, user.ID); !ok {
+				return
+			}
+		}
+
+		if err := queries.RevokeGroupInvite(ctx.Request.Context(), groupInvite.Code); err != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to revoke invite: "+err.Error())
+			gin.DefaultWriter.Write([]byte("Failed to revoke invite: " + err.Error()))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"success": "Revoked invite"})
+	}
+}
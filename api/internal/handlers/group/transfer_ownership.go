@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/middleware"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type TransferOwnershipRequest struct {
+	GroupId uuid.UUID `json:"groupId" binding:"required"`
+	UserId  uuid.UUID `json:"userId" binding:"required"`
+}
+
+// TransferOwnershipHandler hands a group over to another member, who is made
+// an admin if they aren't one already. The previous owner stays an admin.
+func TransferOwnershipHandler(queries *database.Queries) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		var transferRequest TransferOwnershipRequest
+		if bindErr := ctx.Bind(&transferRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+
+		if _, ok := requireOwner(ctx, queries, transferRequest.GroupId, user.ID); !ok {
+			return
+		}
+		if transferRequest.UserId == user.ID {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "User already owns the group"})
+			gin.DefaultWriter.Write([]byte("User already owns the group"))
+			return
+		}
+
+		getMember := database.GetGroupMemberParams{
+			GroupID: transferRequest.GroupId,
+			UserID:  transferRequest.UserId,
+		}
+		member, memberErr := queries.GetGroupMember(ctx.Request.Context(), getMember)
+		if errors.Is(memberErr, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+			gin.DefaultWriter.Write([]byte("Member not found"))
+			return
+		}
+		if memberErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch member: "+memberErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch member: " + memberErr.Error()))
+			return
+		}
+
+		// Promote first so the group never has an owner who isn't an admin.
+		// Both updates share a transaction so a failed transfer doesn't leave
+		// the member promoted.
+		var friendGroup database.FriendGroup
+		txErr := queries.InTx(ctx.Request.Context(), func(queries *database.Queries) error {
+			if member.Role != database.GroupRoleADMIN {
+				promote := database.UpdateMemberRoleParams{
+					GroupID: member.GroupID,
+					UserID:  member.UserID,
+					Role:    database.GroupRoleADMIN,
+				}
+				if _, err := queries.UpdateMemberRole(ctx.Request.Context(), promote); err != nil {
+					return err
+				}
+			}
+
+			updateOwner := database.UpdateFriendGroupOwnerParams{
+				ID:      transferRequest.GroupId,
+				OwnerID: member.UserID,
+			}
+			var updateErr error
+			friendGroup, updateErr = queries.UpdateFriendGroupOwner(ctx.Request.Context(), updateOwner)
+			return updateErr
+		})
+		if txErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to transfer ownership: "+txErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to transfer ownership: " + txErr.Error()))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, friendGroup)
+	}
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/middleware"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type UpdateMemberRoleRequest struct {
+	GroupId uuid.UUID          `json:"groupId" binding:"required"`
+	UserId  uuid.UUID          `json:"userId" binding:"required"`
+	Role    database.GroupRole `json:"role" binding:"required"`
+}
+
+// UpdateMemberRoleHandler promotes or demotes a member. Any admin can promote
+// a member, but only the owner can demote an admin. The owner is always an
+// admin.
+func UpdateMemberRoleHandler(queries *database.Queries) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		var roleRequest UpdateMemberRoleRequest
+		if bindErr := ctx.Bind(&roleRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+		if roleRequest.Role != database.GroupRoleADMIN && roleRequest.Role != database.GroupRoleMEMBER {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role"})
+			gin.DefaultWriter.Write([]byte("Invalid role: " + string(roleRequest.Role)))
+			return
+		}
+
+		if _, ok := requireAdmin(ctx, queries, roleRequest.GroupId, user.ID); !ok {
+			return
+		}
+
+		getMember := database.GetGroupMemberParams{
+			GroupID: roleRequest.GroupId,
+			UserID:  roleRequest.UserId,
+		}
+		member, memberErr := queries.GetGroupMember(ctx.Request.Context(), getMember)
+		if errors.Is(memberErr, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+			gin.DefaultWriter.Write([]byte("Member not found"))
+			return
+		}
+		if memberErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch member: "+memberErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch member: " + memberErr.Error()))
+			return
+		}
+		if member.Role == roleRequest.Role {
+			ctx.JSON(http.StatusOK, member)
+			return
+		}
+
+		friendGroup, groupErr := queries.GetFriendGroup(ctx.Request.Context(), roleRequest.GroupId)
+		if groupErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch group: "+groupErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch group: " + groupErr.Error()))
+			return
+		}
+		if member.UserID == friendGroup.OwnerID {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "The owner's role cannot be changed"})
+			gin.DefaultWriter.Write([]byte("Tried to change the owner's role"))
+			return
+		}
+		if member.Role == database.GroupRoleADMIN && friendGroup.OwnerID != user.ID {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized access: Only the owner can demote admins"))
+			return
+		}
+
+		updateRole := database.UpdateMemberRoleParams{
+			GroupID: member.GroupID,
+			UserID:  member.UserID,
+			Role:    roleRequest.Role,
+		}
+		updated, updateErr := queries.UpdateMemberRole(ctx.Request.Context(), updateRole)
+		if updateErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to update role: "+updateErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to update role: " + updateErr.Error()))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, updated)
+	}
+}
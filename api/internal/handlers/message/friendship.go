@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// areFriends reports whether the two users have an ACCEPTED friendship.
+// Direct messages are only allowed between friends.
+func areFriends(ctx context.Context, queries *database.Queries, userID uuid.UUID, friendID uuid.UUID) (bool, error) {
+	getFriendship := database.GetFriendshipBetweenParams{
+		UserID:   userID,
+		FriendID: friendID,
+	}
+	friendship, err := queries.GetFriendshipBetween(ctx, getFriendship)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return friendship.Status == database.FriendshipStatusACCEPTED, nil
+}
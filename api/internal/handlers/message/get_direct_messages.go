@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/utils"
+	"api/internal/middleware"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PaginatedDirectMessagesResponse represents the response structure for a
+// page of a conversation.
+type PaginatedDirectMessagesResponse struct {
+	Messages []database.DirectMessage `json:"messages"`
+	Offset   uuid.UUID                `json:"offset,omitempty"`
+	HasMore  bool                     `json:"hasMore"`
+}
+
+// GetDirectMessagesHandler lists the conversation with a friend newest first,
+// paginated with the ID of the oldest message seen so far as the cursor.
+func GetDirectMessagesHandler(queries *database.Queries) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		friendID, friendIdParseErr := uuid.Parse(ctx.Query("friendId"))
+		if friendIdParseErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid friendId"})
+			gin.DefaultWriter.Write([]byte("Invalid friendId: " + friendIdParseErr.Error()))
+			return
+		}
+
+		friends, friendsErr := areFriends(ctx.Request.Context(), queries, user.ID, friendID)
+		if friendsErr != nil {
+			ctx.String(http.StatusInternalServerError, "Error: Failed to retrieve friendship")
+			gin.DefaultWriter.Write([]byte("Failed to fetch friendship: " + friendsErr.Error()))
+			return
+		}
+		if !friends {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized: Users are not friends"))
+			return
+		}
+
+		// Get pagination parameters
+		limitStr := ctx.DefaultQuery("limit", "30")
+		limit, limitErr := strconv.Atoi(limitStr)
+		if limitErr != nil || limit <= 0 || limit > 100 {
+			limit = 30 // Default to 30 if invalid
+		}
+
+		var messages []database.DirectMessage
+		offsetString := ctx.Query("offset") // Empty if not provided
+		if offsetString != "" {
+			offset, convErr := uuid.Parse(offsetString)
+			if convErr != nil {
+				ctx.String(http.StatusBadRequest, "Invalid cursor")
+				gin.DefaultWriter.Write([]byte("Invalid cursor: " + convErr.Error()))
+				return
+			}
+
+			// Fetch the creation time of the message the cursor points at
+			cursor, cursorErr := queries.GetDirectMessage(ctx.Request.Context(), offset)
+			if cursorErr != nil {
+				ctx.String(http.StatusBadRequest, "Invalid cursor")
+				gin.DefaultWriter.Write([]byte("Invalid cursor: " + cursorErr.Error()))
+				return
+			}
+
+			params := database.ListPaginatedDirectMessagesParams{
+				SenderID:    user.ID,
+				RecipientID: friendID,
+				DateCreated: cursor.DateCreated,
+				Column4:     offset,
+				Limit:       int32(limit + 1),
+			}
+			page, listErr := queries.ListPaginatedDirectMessages(ctx.Request.Context(), params)
+			if listErr != nil {
+				ctx.String(http.StatusInternalServerError, "Error: Failed to retrieve messages: "+listErr.Error())
+				gin.DefaultWriter.Write([]byte("Error: Failed to retrieve messages: " + listErr.Error()))
+				return
+			}
+			messages = page
+		} else {
+			// Initial fetch without offset
+			params := database.InitialDirectMessagesParams{
+				SenderID:    user.ID,
+				RecipientID: friendID,
+				Limit:       int32(limit + 1),
+			}
+			page, initialErr := queries.InitialDirectMessages(ctx.Request.Context(), params)
+			if initialErr != nil {
+				ctx.String(http.StatusInternalServerError, "Error: Failed to retrieve messages: "+initialErr.Error())
+				gin.DefaultWriter.Write([]byte("Error: Failed to retrieve messages: " + initialErr.Error()))
+				return
+			}
+			messages = page
+		}
+		if messages == nil {
+			messages = []database.DirectMessage{}
+		}
+
+		// Check if there are more messages
+		hasMore := false
+		if len(messages) > limit {
+			hasMore = true
+			messages = messages[:limit] // Remove the extra message
+		}
+
+		response := PaginatedDirectMessagesResponse{
+			Messages: messages,
+			HasMore:  hasMore,
+		}
+		// Only include the cursor if there are more messages
+		if hasMore {
+			response.Offset = messages[len(messages)-1].ID
+		}
+
+		responseJSON, err := json.Marshal(response)
+		if err != nil {
+			ctx.String(http.StatusInternalServerError, "Error generating response")
+			gin.DefaultWriter.Write([]byte("Error generating response: " + err.Error()))
+			return
+		}
+
+		// New messages and read receipts change the page, so always revalidate.
+		if handled := utils.AttachCacheHeaders(ctx, responseJSON, 0); handled {
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response)
+	}
+}
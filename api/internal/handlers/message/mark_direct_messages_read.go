@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/producer"
+	"api/internal/core/utils"
+	"api/internal/middleware"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// MarkDirectMessagesReadRequest marks the friend's messages as read up to and
+// including MessageId, or all of them when it is omitted.
+type MarkDirectMessagesReadRequest struct {
+	FriendId  uuid.UUID  `json:"friendId" binding:"required"`
+	MessageId *uuid.UUID `json:"messageId"`
+}
+
+// MarkDirectMessagesReadHandler records a read receipt and tells the sender,
+// and the reader's other devices, over the websocket hub.
+func MarkDirectMessagesReadHandler(queries *database.Queries, publisher *producer.Publisher) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		var readRequest MarkDirectMessagesReadRequest
+		if bindErr := ctx.Bind(&readRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+
+		friends, friendsErr := areFriends(ctx.Request.Context(), queries, user.ID, readRequest.FriendId)
+		if friendsErr != nil {
+			ctx.String(http.StatusInternalServerError, "Error: Failed to retrieve friendship")
+			gin.DefaultWriter.Write([]byte("Failed to fetch friendship: " + friendsErr.Error()))
+			return
+		}
+		if !friends {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized: Users are not friends"))
+			return
+		}
+
+		now := utils.PGTime()
+		until := now
+		if readRequest.MessageId != nil {
+			directMessage, messageErr := queries.GetDirectMessage(ctx.Request.Context(), *readRequest.MessageId)
+			if errors.Is(messageErr, pgx.ErrNoRows) || (messageErr == nil && (directMessage.SenderID != readRequest.FriendId || directMessage.RecipientID != user.ID)) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "Message not found"})
+				gin.DefaultWriter.Write([]byte("Message not found"))
+				return
+			}
+			if messageErr != nil {
+				ctx.String(http.StatusInternalServerError, "Failed to fetch message: "+messageErr.Error())
+				gin.DefaultWriter.Write([]byte("Failed to fetch message: " + messageErr.Error()))
+				return
+			}
+			until = directMessage.DateCreated
+		}
+
+		markRead := database.MarkDirectMessagesReadParams{
+			RecipientID: user.ID,
+			SenderID:    readRequest.FriendId,
+			DateRead:    now,
+			DateCreated: until,
+		}
+		marked, markErr := queries.MarkDirectMessagesRead(ctx.Request.Context(), markRead)
+		if markErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to mark messages read: "+markErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to mark messages read: " + markErr.Error()))
+			return
+		}
+
+		// Only tell anyone when a message actually changed, so repeated calls
+		// don't produce a stream of identical receipts.
+		if marked > 0 {
+			publisher.DirectMessagesRead(producer.DirectRead{
+				ReaderID: user.ID,
+				SenderID: readRequest.FriendId,
+				Until:    until.Time,
+			})
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"success": "Marked messages read"})
+	}
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/message"
+	"api/internal/core/notifications"
+	"api/internal/core/producer"
+	"api/internal/core/utils"
+	"api/internal/middleware"
+	"log"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"firebase.google.com/go/v4/messaging"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const maxDirectMessageLength = 2000
+
+type SendDirectMessageRequest struct {
+	RecipientId uuid.UUID `json:"recipientId" binding:"required"`
+	Content     string    `json:"content" binding:"required"`
+}
+
+func validDirectMessage(content string) bool {
+	content = strings.TrimSpace(content)
+	return content != "" && utf8.RuneCountInString(content) <= maxDirectMessageLength
+}
+
+// SendDirectMessageHandler stores a message to a friend and delivers it over
+// the recipient's websockets. Recipients without an open socket on any
+// replica get a push notification instead.
+func SendDirectMessageHandler(
+	queries *database.Queries,
+	messagingClient *messaging.Client,
+	hub *message.Hub,
+	publisher *producer.Publisher,
+) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		var messageRequest SendDirectMessageRequest
+		if bindErr := ctx.Bind(&messageRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+		if messageRequest.RecipientId == user.ID {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Cannot message yourself"})
+			gin.DefaultWriter.Write([]byte("Cannot message yourself"))
+			return
+		}
+		if !validDirectMessage(messageRequest.Content) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message"})
+			gin.DefaultWriter.Write([]byte("Invalid message"))
+			return
+		}
+
+		friends, friendsErr := areFriends(ctx.Request.Context(), queries, user.ID, messageRequest.RecipientId)
+		if friendsErr != nil {
+			ctx.String(http.StatusInternalServerError, "Error: Failed to retrieve friendship")
+			gin.DefaultWriter.Write([]byte("Failed to fetch friendship: " + friendsErr.Error()))
+			return
+		}
+		if !friends {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized: Users are not friends"))
+			return
+		}
+
+		createMessage := database.CreateDirectMessageParams{
+			ID:          uuid.New(),
+			SenderID:    user.ID,
+			RecipientID: messageRequest.RecipientId,
+			Content:     strings.TrimSpace(messageRequest.Content),
+			DateCreated: utils.PGTime(),
+		}
+		directMessage, createErr := queries.CreateDirectMessage(ctx.Request.Context(), createMessage)
+		if createErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to send message: "+createErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to send message: " + createErr.Error()))
+			return
+		}
+
+		publisher.DirectMessageCreated(directMessage)
+
+		online, onlineErr := hub.IsOnline(ctx.Request.Context(), directMessage.RecipientID)
+		if onlineErr != nil {
+			// Better a duplicate alert than a message nobody hears about.
+			log.Printf("Failed to check if user %s is online: %v", directMessage.RecipientID, onlineErr)
+		}
+		if !online {
+			go notifications.SendDirectMessageNotification(
+				queries,
+				&directMessage,
+				&user,
+				messagingClient,
+			)
+		}
+
+		ctx.JSON(http.StatusOK, directMessage)
+	}
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/media"
+	"api/internal/core/storage"
+	"api/internal/middleware"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type CompleteResumableUploadRequest struct {
+	SlotId uuid.UUID `json:"slotId" binding:"required"`
+}
+
+// CompleteResumableUploadHandler assembles the parts of a resumable upload
+// into the final object once every part is in place. The post remains
+// PENDING until CompleteUploadHandler publishes it.
+func CompleteResumableUploadHandler(queries *database.Queries, store storage.Storage) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		multipart, canResume := store.(storage.MultipartUploader)
+		if !canResume {
+			ctx.JSON(http.StatusNotImplemented, gin.H{"error": "Storage does not support resumable uploads"})
+			gin.DefaultWriter.Write([]byte("Storage does not support resumable uploads"))
+			return
+		}
+
+		var completeRequest CompleteResumableUploadRequest
+		if bindErr := ctx.Bind(&completeRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+
+		slot, found := ownedUploadSlot(ctx, queries, user.ID, completeRequest.SlotId)
+		if !found {
+			return
+		}
+		if slot.PartSize == nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Upload is not resumable"})
+			gin.DefaultWriter.Write([]byte("Upload is not resumable"))
+			return
+		}
+		if slot.UploadID == nil {
+			ctx.JSON(http.StatusOK, gin.H{"success": "Upload already assembled"})
+			return
+		}
+
+		parts, listErr := multipart.ListParts(ctx.Request.Context(), slot.ObjectKey, *slot.UploadID)
+		if errors.Is(listErr, storage.ErrNoSuchUpload) {
+			ctx.JSON(http.StatusGone, gin.H{"error": "Upload expired"})
+			gin.DefaultWriter.Write([]byte("Upload expired: " + slot.ID.String()))
+			return
+		}
+		if listErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch upload parts: "+listErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to list parts: " + listErr.Error()))
+			return
+		}
+
+		complete := completeParts(slot, parts)
+		partCount := media.PartCount(slot.Size, *slot.PartSize)
+		ordered := make([]storage.Part, 0, partCount)
+		var missing []int32
+		for number := int32(1); number <= partCount; number++ {
+			part, ok := complete[number]
+			if !ok {
+				missing = append(missing, number)
+				continue
+			}
+			ordered = append(ordered, part)
+		}
+		if len(missing) > 0 {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "Upload has missing parts", "missingParts": missing})
+			gin.DefaultWriter.Write([]byte("Upload has missing parts: " + slot.ID.String()))
+			return
+		}
+
+		if completeErr := multipart.CompleteMultipartUpload(ctx.Request.Context(), slot.ObjectKey, *slot.UploadID, ordered); completeErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to assemble upload: "+completeErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to complete multipart upload: " + completeErr.Error()))
+			return
+		}
+		if markErr := queries.MarkUploadSlotAssembled(ctx.Request.Context(), slot.ID); markErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to update upload: "+markErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to mark upload assembled: " + markErr.Error()))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"success": "Assembled upload"})
+	}
+}
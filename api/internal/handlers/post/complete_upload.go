@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/imaging"
+	"api/internal/core/media"
+	"api/internal/core/producer"
+	"api/internal/core/storage"
+	"api/internal/middleware"
+	"errors"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type CompleteUploadRequest struct {
+	PostId uuid.UUID `json:"postId" binding:"required"`
+}
+
+// CompleteUploadHandler checks that every upload slot of a PENDING post was
+// filled with the size and content type the client asked for, then moves the
+// post to PUBLISHED. Slots that haven't been uploaded yet, or resumable
+// uploads whose parts haven't been assembled, leave the post PENDING so the
+// client can retry. Uploads that don't match fail the post.
+func CompleteUploadHandler(queries *database.Queries, store storage.Storage, publisher *producer.Publisher) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		var completeRequest CompleteUploadRequest
+		if bindErr := ctx.Bind(&completeRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+
+		post, postErr := queries.GetPost(ctx.Request.Context(), completeRequest.PostId)
+		if errors.Is(postErr, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+			gin.DefaultWriter.Write([]byte("Post not found"))
+			return
+		}
+		if postErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch post: "+postErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch post: " + postErr.Error()))
+			return
+		}
+		if post.UserID != user.ID {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized: Post belongs to another user"))
+			return
+		}
+		if post.Status != database.PostStatusPENDING {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "Post is not pending"})
+			gin.DefaultWriter.Write([]byte("Post is not pending"))
+			return
+		}
+
+		slots, listErr := queries.ListUploadSlotsForPost(ctx.Request.Context(), post.ID)
+		if listErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch uploads: "+listErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch uploads: " + listErr.Error()))
+			return
+		}
+		if len(slots) == 0 {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "No uploads to complete"})
+			gin.DefaultWriter.Write([]byte("No uploads to complete"))
+			return
+		}
+
+		for _, slot := range slots {
+			if slot.UploadID != nil {
+				ctx.JSON(http.StatusConflict, gin.H{"error": "Upload not finished", "slotId": slot.ID})
+				gin.DefaultWriter.Write([]byte("Upload not assembled: " + slot.ID.String()))
+				return
+			}
+			info, statErr := store.Stat(ctx.Request.Context(), slot.ObjectKey)
+			if errors.Is(statErr, storage.ErrNotFound) {
+				ctx.JSON(http.StatusConflict, gin.H{"error": "Upload not finished", "slotId": slot.ID})
+				gin.DefaultWriter.Write([]byte("Upload not finished: " + slot.ID.String()))
+				return
+			}
+			if statErr != nil {
+				ctx.String(http.StatusInternalServerError, "Failed to check upload: "+statErr.Error())
+				gin.DefaultWriter.Write([]byte("Failed to check upload: " + statErr.Error()))
+				return
+			}
+			if info.Size != slot.Size || !sameContentType(info.ContentType, slot.ContentType) {
+				failUpload(ctx, queries, store, post, slots)
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Upload does not match", "slotId": slot.ID})
+				gin.DefaultWriter.Write([]byte("Upload does not match: " + slot.ID.String()))
+				return
+			}
+			// The stored content type is whatever the client sent, so the
+			// file itself has to agree with it.
+			sniffed, sniffErr := media.SniffUpload(ctx.Request.Context(), store, slot.ObjectKey)
+			if sniffErr != nil {
+				ctx.String(http.StatusInternalServerError, "Failed to check upload: "+sniffErr.Error())
+				gin.DefaultWriter.Write([]byte("Failed to check upload: " + sniffErr.Error()))
+				return
+			}
+			if sniffed != slot.ContentType {
+				failUpload(ctx, queries, store, post, slots)
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Upload does not match", "slotId": slot.ID})
+				gin.DefaultWriter.Write([]byte("Upload content is " + sniffed + ", not " + slot.ContentType + ": " + slot.ID.String()))
+				return
+			}
+		}
+
+		claimed, claimErr := queries.ClaimUploadSlotsForPost(ctx.Request.Context(), post.ID)
+		if claimErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to complete upload: "+claimErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to claim upload slots: " + claimErr.Error()))
+			return
+		}
+		if len(claimed) == 0 {
+			// A concurrent request got here first.
+			ctx.JSON(http.StatusConflict, gin.H{"error": "Upload already completed"})
+			gin.DefaultWriter.Write([]byte("Upload already completed"))
+			return
+		}
+		if createErr := media.CreateUploadedMedia(ctx.Request.Context(), queries, store, &post, claimed); createErr != nil {
+			markFailed(ctx, queries, post)
+			if errors.Is(createErr, imaging.ErrUnsupported) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported image format"})
+				gin.DefaultWriter.Write([]byte("Unsupported image format: " + createErr.Error()))
+				return
+			}
+			if errors.Is(createErr, imaging.ErrTooLarge) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Image too large"})
+				gin.DefaultWriter.Write([]byte("Image too large: " + createErr.Error()))
+				return
+			}
+			ctx.String(http.StatusInternalServerError, "Failed to create media: "+createErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to create media: " + createErr.Error()))
+			return
+		}
+
+		if publishErr := media.Publish(ctx.Request.Context(), queries, publisher, &post); publishErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to publish post: "+publishErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to publish post: " + publishErr.Error()))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, post)
+	}
+}
+
+// sameContentType compares media types, ignoring parameters and case.
+func sameContentType(stored string, expected string) bool {
+	storedType, _, err := mime.ParseMediaType(stored)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(storedType, expected)
+}
+
+// failUpload fails the post and throws its uploads away, since the client
+// can't fix a mismatched upload without starting over.
+func failUpload(ctx *gin.Context, queries *database.Queries, store storage.Storage, post database.Post, slots []database.UploadSlot) {
+	markFailed(ctx, queries, post)
+	keys := make([]string, 0, len(slots))
+	for _, slot := range slots {
+		keys = append(keys, slot.ObjectKey)
+	}
+	if err := store.Delete(ctx.Request.Context(), keys); err != nil {
+		log.Println("Failed to delete uploads for post " + post.ID.String() + ": " + err.Error())
+	}
+	if _, err := queries.ClaimUploadSlotsForPost(ctx.Request.Context(), post.ID); err != nil {
+		log.Println("Failed to delete upload slots for post " + post.ID.String() + ": " + err.Error())
+	}
+}
+
+func markFailed(ctx *gin.Context, queries *database.Queries, post database.Post) {
+	postStatus := database.UpdatePostStatusParams{
+		ID:     post.ID,
+		Status: database.PostStatusFAILED,
+	}
+	if err := queries.UpdatePostStatus(ctx.Request.Context(), postStatus); err != nil {
+		log.Println("Failed to mark post " + post.ID.String() + " failed: " + err.Error())
+	}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/notifications"
+	"api/internal/core/producer"
+	"api/internal/core/utils"
+	"api/internal/middleware"
+	"errors"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"firebase.google.com/go/v4/messaging"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const maxCommentLength = 2000
+
+type CreateCommentRequest struct {
+	PostId   uuid.UUID  `json:"postId" binding:"required"`
+	GroupId  uuid.UUID  `json:"groupId" binding:"required"`
+	ParentId *uuid.UUID `json:"parentId"`
+	Content  string     `json:"content" binding:"required"`
+}
+
+func validComment(content string) bool {
+	content = strings.TrimSpace(content)
+	return content != "" && utf8.RuneCountInString(content) <= maxCommentLength
+}
+
+func CreateCommentHandler(queries *database.Queries, messagingClient *messaging.Client, publisher *producer.Publisher) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		var commentRequest CreateCommentRequest
+		if bindErr := ctx.Bind(&commentRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+		if !validComment(commentRequest.Content) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment"})
+			gin.DefaultWriter.Write([]byte("Invalid comment"))
+			return
+		}
+
+		checkMembership := database.CheckUserMembershipParams{
+			GroupID: commentRequest.GroupId,
+			UserID:  user.ID,
+		}
+		isMember, checkErr := queries.CheckUserMembership(ctx.Request.Context(), checkMembership)
+		if checkErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to check membership: "+checkErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to check membership: " + checkErr.Error()))
+			return
+		}
+		if !isMember {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		if !requireVisiblePost(ctx, queries, commentRequest.PostId, user.ID) {
+			return
+		}
+
+		checkPost := database.CheckPostInGroupParams{
+			GroupID: commentRequest.GroupId,
+			PostID:  commentRequest.PostId,
+		}
+		inGroup, inGroupErr := queries.CheckPostInGroup(ctx.Request.Context(), checkPost)
+		if inGroupErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to check post: "+inGroupErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to check post: " + inGroupErr.Error()))
+			return
+		}
+		if !inGroup {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Post not found in group"})
+			gin.DefaultWriter.Write([]byte("Post not found in group"))
+			return
+		}
+
+		// Replies only go one level deep and must stay in the parent's thread.
+		if commentRequest.ParentId != nil {
+			parent, parentErr := queries.GetComment(ctx.Request.Context(), *commentRequest.ParentId)
+			if errors.Is(parentErr, pgx.ErrNoRows) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Parent comment not found"})
+				gin.DefaultWriter.Write([]byte("Parent comment not found"))
+				return
+			}
+			if parentErr != nil {
+				ctx.String(http.StatusInternalServerError, "Failed to fetch parent comment: "+parentErr.Error())
+				gin.DefaultWriter.Write([]byte("Failed to fetch parent comment: " + parentErr.Error()))
+				return
+			}
+			if parent.PostID != commentRequest.PostId || parent.GroupID != commentRequest.GroupId {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Parent comment belongs to a different post"})
+				gin.DefaultWriter.Write([]byte("Parent comment belongs to a different post"))
+				return
+			}
+			if parent.ParentID != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Cannot reply to a reply"})
+				gin.DefaultWriter.Write([]byte("Cannot reply to a reply"))
+				return
+			}
+		}
+
+		post, postErr := queries.GetPost(ctx.Request.Context(), commentRequest.PostId)
+		if postErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch post: "+postErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch post: " + postErr.Error()))
+			return
+		}
+
+		createComment := database.CreateCommentParams{
+			ID:          uuid.New(),
+			PostID:      commentRequest.PostId,
+			GroupID:     commentRequest.GroupId,
+			UserID:      user.ID,
+			ParentID:    commentRequest.ParentId,
+			Content:     strings.TrimSpace(commentRequest.Content),
+			DateCreated: utils.PGTime(),
+		}
+		comment, createErr := queries.CreateComment(ctx.Request.Context(), createComment)
+		if createErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to create comment: "+createErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to create comment: " + createErr.Error()))
+			return
+		}
+
+		publisher.CommentCreated(comment)
+		go notifications.SendCommentNotification(
+			queries,
+			&post,
+			&comment,
+			&user,
+			messagingClient,
+		)
+
+		ctx.JSON(http.StatusOK, comment)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	database "api/internal/core/db"
 	"api/internal/core/media"
 	"api/internal/core/notifications"
+	"api/internal/core/producer"
 	"api/internal/core/score"
+	"api/internal/core/storage"
 	"api/internal/core/utils"
 	"api/internal/middleware"
 	"encoding/json"
@@ -21,7 +23,7 @@ type CreatePostRequest struct {
 	Groups  []uuid.UUID `json:"groups"`
 }
 
-func CreatePostHandler(queries *database.Queries, messagingClient *messaging.Client) gin.HandlerFunc {
+func CreatePostHandler(queries *database.Queries, messagingClient *messaging.Client, store storage.Storage, publisher *producer.Publisher, scheduler *score.Scheduler) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		//@TODO: Maybe add some permissions here
 		token, tokenErr := middleware.GetAuthToken(ctx)
@@ -67,6 +69,8 @@ func CreatePostHandler(queries *database.Queries, messagingClient *messaging.Cli
 			postMedia = database.MediaTypeTEXT
 		case "OTHER":
 			postMedia = database.MediaTypeOTHER
+		case "CAROUSEL":
+			postMedia = database.MediaTypeCAROUSEL
 		}
 
 		postParams := database.CreatePostParams{
@@ -84,8 +88,6 @@ func CreatePostHandler(queries *database.Queries, messagingClient *messaging.Cli
 			return
 		}
 
-		go media.CreateMedia(queries, &post, ctx)
-
 		checkMembership := database.CheckUserMembershipForGroupsParams{
 			UserID:  user.ID,
 			Column2: createRequest.Groups,
@@ -112,6 +114,15 @@ func CreatePostHandler(queries *database.Queries, messagingClient *messaging.Cli
 				return
 			}
 		}
+		// Groups are attached first so CreateMedia can announce the post to
+		// them once it is published. Images, videos and carousels sent
+		// without a file stay PENDING until the client completes a direct
+		// upload.
+		_, hasFile := ctx.Request.MultipartForm.File["file"]
+		directUpload := !hasFile && media.TakesUploads(post.Media)
+		if !directUpload {
+			go media.CreateMedia(queries, store, publisher, &post, ctx)
+		}
 		go notifications.SendPostNotification(
 			queries,
 			&post,
@@ -121,7 +132,7 @@ func CreatePostHandler(queries *database.Queries, messagingClient *messaging.Cli
 		)
 
 		for _, groupID := range createRequest.Groups {
-			go score.RunScoreCalculation(groupID, queries)
+			scheduler.Trigger(groupID)
 		}
 
 		ctx.JSON(http.StatusOK, post)
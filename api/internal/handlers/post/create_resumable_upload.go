@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/media"
+	"api/internal/core/storage"
+	"api/internal/core/utils"
+	"api/internal/middleware"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type CreateResumableUploadRequest struct {
+	PostId      uuid.UUID `json:"postId" binding:"required"`
+	ContentType string    `json:"contentType" binding:"required"`
+	Size        int64     `json:"size" binding:"required"`
+}
+
+type ResumableUploadResponse struct {
+	SlotId    uuid.UUID `json:"slotId"`
+	Position  int32     `json:"position"`
+	PartSize  int64     `json:"partSize"`
+	PartCount int32     `json:"partCount"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// CreateResumableUploadHandler starts a multipart upload for one file of a
+// PENDING post. The client uploads the parts through PresignUploadParts,
+// retrying any part that fails, then assembles them with
+// CompleteResumableUpload. The post stays PENDING throughout and is published
+// by CompleteUploadHandler as usual.
+func CreateResumableUploadHandler(queries *database.Queries, store storage.Storage) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		multipart, canResume := store.(storage.MultipartUploader)
+		if !canResume {
+			ctx.JSON(http.StatusNotImplemented, gin.H{"error": "Storage does not support resumable uploads"})
+			gin.DefaultWriter.Write([]byte("Storage does not support resumable uploads"))
+			return
+		}
+
+		var uploadRequest CreateResumableUploadRequest
+		if bindErr := ctx.Bind(&uploadRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+
+		post, postErr := queries.GetPost(ctx.Request.Context(), uploadRequest.PostId)
+		if errors.Is(postErr, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+			gin.DefaultWriter.Write([]byte("Post not found"))
+			return
+		}
+		if postErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch post: "+postErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch post: " + postErr.Error()))
+			return
+		}
+		if post.UserID != user.ID {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized: Post belongs to another user"))
+			return
+		}
+		if post.Status != database.PostStatusPENDING {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "Post is not pending"})
+			gin.DefaultWriter.Write([]byte("Post is not pending"))
+			return
+		}
+		if !media.TakesUploads(post.Media) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Post media does not take uploads"})
+			gin.DefaultWriter.Write([]byte("Post media does not take uploads"))
+			return
+		}
+
+		slotID := uuid.New()
+		key, ok := media.UploadKey(post.Media, slotID, uploadRequest.ContentType)
+		if !ok {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported content type " + uploadRequest.ContentType})
+			gin.DefaultWriter.Write([]byte("Unsupported content type " + uploadRequest.ContentType))
+			return
+		}
+		if uploadRequest.Size <= 0 || uploadRequest.Size > media.MaxUploadSize(post.Media, uploadRequest.ContentType) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "File too large"})
+			gin.DefaultWriter.Write([]byte("File too large"))
+			return
+		}
+
+		uploadID, startErr := multipart.CreateMultipartUpload(ctx.Request.Context(), key, uploadRequest.ContentType)
+		if startErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to create upload: "+startErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to start multipart upload: " + startErr.Error()))
+			return
+		}
+		partSize := media.PartSize(uploadRequest.Size)
+		expiresAt := time.Now().Add(media.ResumableUploadExpiry)
+		createSlot := database.CreateUploadSlotParams{
+			ID:          slotID,
+			ObjectKey:   key,
+			ContentType: uploadRequest.ContentType,
+			Size:        uploadRequest.Size,
+			DateCreated: utils.PGTime(),
+			ExpiresAt:   pgtype.Timestamptz{Time: expiresAt, Valid: true},
+			UploadID:    &uploadID,
+			PartSize:    &partSize,
+		}
+		// Resumable uploads are placed in the order they are started.
+		created, createErr := media.CreateUploadSlots(ctx.Request.Context(), queries, post.ID, []database.CreateUploadSlotParams{createSlot})
+		if createErr != nil {
+			multipart.AbortMultipartUpload(ctx.Request.Context(), key, uploadID)
+			writeUploadSlotsError(ctx, createErr)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, ResumableUploadResponse{
+			SlotId:    slotID,
+			Position:  created[0].Position,
+			PartSize:  partSize,
+			PartCount: media.PartCount(uploadRequest.Size, partSize),
+			ExpiresAt: expiresAt,
+		})
+	}
+}
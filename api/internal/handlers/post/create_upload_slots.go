@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/media"
+	"api/internal/core/storage"
+	"api/internal/core/utils"
+	"api/internal/middleware"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// uploadURLExpiry is how long a presigned upload URL stays valid.
+const uploadURLExpiry = 15 * time.Minute
+
+type UploadFile struct {
+	ContentType string `json:"contentType" binding:"required"`
+	Size        int64  `json:"size" binding:"required"`
+}
+
+type CreateUploadSlotsRequest struct {
+	PostId uuid.UUID    `json:"postId" binding:"required"`
+	Files  []UploadFile `json:"files" binding:"required"`
+}
+
+// UploadSlotResponse tells the client where to PUT one file. The request
+// must carry the returned headers exactly, since they are signed.
+type UploadSlotResponse struct {
+	SlotId    uuid.UUID                `json:"slotId"`
+	Position  int32                    `json:"position"`
+	Upload    storage.PresignedRequest `json:"upload"`
+	ExpiresAt time.Time                `json:"expiresAt"`
+}
+
+// CreateUploadSlotsHandler hands out presigned URLs so the client can upload
+// the media of a PENDING image, video or carousel post straight to storage. The post
+// is published by CompleteUploadHandler once the uploads are in place.
+func CreateUploadSlotsHandler(queries *database.Queries, store storage.Storage) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		presigner, canPresign := store.(storage.Presigner)
+		if !canPresign {
+			ctx.JSON(http.StatusNotImplemented, gin.H{"error": "Storage does not support direct uploads"})
+			gin.DefaultWriter.Write([]byte("Storage does not support direct uploads"))
+			return
+		}
+
+		var slotsRequest CreateUploadSlotsRequest
+		if bindErr := ctx.Bind(&slotsRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+
+		post, postErr := queries.GetPost(ctx.Request.Context(), slotsRequest.PostId)
+		if errors.Is(postErr, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+			gin.DefaultWriter.Write([]byte("Post not found"))
+			return
+		}
+		if postErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch post: "+postErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch post: " + postErr.Error()))
+			return
+		}
+		if post.UserID != user.ID {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized: Post belongs to another user"))
+			return
+		}
+		if post.Status != database.PostStatusPENDING {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "Post is not pending"})
+			gin.DefaultWriter.Write([]byte("Post is not pending"))
+			return
+		}
+		if !media.TakesUploads(post.Media) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Post media does not take uploads"})
+			gin.DefaultWriter.Write([]byte("Post media does not take uploads"))
+			return
+		}
+
+		if len(slotsRequest.Files) == 0 || len(slotsRequest.Files) > media.MaxUploadSlots {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid number of files"})
+			gin.DefaultWriter.Write([]byte("Invalid number of files"))
+			return
+		}
+
+		// Validate everything before handing out any URL.
+		keys := make([]string, len(slotsRequest.Files))
+		slotIDs := make([]uuid.UUID, len(slotsRequest.Files))
+		for i, file := range slotsRequest.Files {
+			slotIDs[i] = uuid.New()
+			key, ok := media.UploadKey(post.Media, slotIDs[i], file.ContentType)
+			if !ok {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported content type " + file.ContentType, "index": i})
+				gin.DefaultWriter.Write([]byte("Unsupported content type " + file.ContentType))
+				return
+			}
+			if file.Size <= 0 || file.Size > media.MaxUploadSize(post.Media, file.ContentType) {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "File too large", "index": i})
+				gin.DefaultWriter.Write([]byte("File too large"))
+				return
+			}
+			keys[i] = key
+		}
+
+		expiresAt := time.Now().Add(uploadURLExpiry)
+		uploads := make([]storage.PresignedRequest, len(slotsRequest.Files))
+		createSlots := make([]database.CreateUploadSlotParams, len(slotsRequest.Files))
+		for i, file := range slotsRequest.Files {
+			upload, presignErr := presigner.PresignPut(ctx.Request.Context(), keys[i], file.ContentType, file.Size, uploadURLExpiry)
+			if presignErr != nil {
+				ctx.String(http.StatusInternalServerError, "Failed to create upload: "+presignErr.Error())
+				gin.DefaultWriter.Write([]byte("Failed to presign upload: " + presignErr.Error()))
+				return
+			}
+			uploads[i] = upload
+			createSlots[i] = database.CreateUploadSlotParams{
+				ID:          slotIDs[i],
+				ObjectKey:   keys[i],
+				ContentType: file.ContentType,
+				Size:        file.Size,
+				DateCreated: utils.PGTime(),
+				ExpiresAt:   pgtype.Timestamptz{Time: expiresAt, Valid: true},
+			}
+		}
+
+		// Files are placed after any slots the post already has, in the
+		// order they were listed.
+		created, createErr := media.CreateUploadSlots(ctx.Request.Context(), queries, post.ID, createSlots)
+		if createErr != nil {
+			writeUploadSlotsError(ctx, createErr)
+			return
+		}
+
+		slots := make([]UploadSlotResponse, len(created))
+		for i, slot := range created {
+			slots[i] = UploadSlotResponse{
+				SlotId:    slot.ID,
+				Position:  slot.Position,
+				Upload:    uploads[i],
+				ExpiresAt: expiresAt,
+			}
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"slots": slots})
+	}
+}
+
+// writeUploadSlotsError writes the response for an error returned by
+// media.CreateUploadSlots.
+func writeUploadSlotsError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		gin.DefaultWriter.Write([]byte("Post not found"))
+	case errors.Is(err, media.ErrNotPending):
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Post is not pending"})
+		gin.DefaultWriter.Write([]byte("Post is not pending"))
+	case errors.Is(err, media.ErrTooManyUploads):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid number of files"})
+		gin.DefaultWriter.Write([]byte("Invalid number of files"))
+	default:
+		ctx.String(http.StatusInternalServerError, "Failed to create upload: "+err.Error())
+		gin.DefaultWriter.Write([]byte("Failed to create upload slot: " + err.Error()))
+	}
+}
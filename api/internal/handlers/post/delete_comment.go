@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/producer"
+	"api/internal/middleware"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type DeleteCommentRequest struct {
+	CommentId uuid.UUID `json:"commentId" binding:"required"`
+}
+
+// DeleteCommentHandler lets the comment's author or the post's owner remove a
+// comment. Replies are removed along with their parent.
+func DeleteCommentHandler(queries *database.Queries, publisher *producer.Publisher) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		var deleteRequest DeleteCommentRequest
+		if bindErr := ctx.Bind(&deleteRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+
+		comment, commentErr := queries.GetComment(ctx.Request.Context(), deleteRequest.CommentId)
+		if errors.Is(commentErr, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+			gin.DefaultWriter.Write([]byte("Comment not found"))
+			return
+		}
+		if commentErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch comment: "+commentErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch comment: " + commentErr.Error()))
+			return
+		}
+
+		if comment.UserID != user.ID {
+			checkPost := database.CheckPostOwnerParams{
+				UserID: user.ID,
+				ID:     comment.PostID,
+			}
+			isOwner, checkErr := queries.CheckPostOwner(ctx.Request.Context(), checkPost)
+			if checkErr != nil {
+				ctx.String(http.StatusInternalServerError, "Failed to check post ownership: "+checkErr.Error())
+				gin.DefaultWriter.Write([]byte("Failed to check post ownership: " + checkErr.Error()))
+				return
+			}
+			if !isOwner {
+				ctx.String(http.StatusUnauthorized, "Unauthorized")
+				gin.DefaultWriter.Write([]byte("Unauthorized"))
+				return
+			}
+		}
+
+		if err := queries.DeleteComment(ctx.Request.Context(), comment.ID); err != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to delete comment: "+err.Error())
+			gin.DefaultWriter.Write([]byte("Failed to delete comment: " + err.Error()))
+			return
+		}
+
+		publisher.CommentDeleted(comment)
+
+		ctx.JSON(http.StatusOK, gin.H{"success": "Deleted comment"})
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/media"
+	"api/internal/core/producer"
+	"api/internal/core/storage"
+	"api/internal/middleware"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type DeletePostRequest struct {
+	PostId uuid.UUID `json:"postId" binding:"required"`
+}
+
+// DeletePostHandler deletes a post from every group along with its comments,
+// reactions and uploaded media.
+func DeletePostHandler(queries *database.Queries, store storage.Storage, publisher *producer.Publisher) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		var deleteRequest DeletePostRequest
+		if bindErr := ctx.Bind(&deleteRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+
+		post, postErr := queries.GetPost(ctx.Request.Context(), deleteRequest.PostId)
+		if errors.Is(postErr, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+			gin.DefaultWriter.Write([]byte("Post not found"))
+			return
+		}
+		if postErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch post: "+postErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch post: " + postErr.Error()))
+			return
+		}
+		if post.UserID != user.ID {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+
+		// Everything below cascades from the post, so collect it first.
+		groupIDs, groupsErr := queries.ListGroupsForPost(ctx.Request.Context(), post.ID)
+		if groupsErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch post groups: "+groupsErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch post groups: " + groupsErr.Error()))
+			return
+		}
+		objectKeys, keysErr := media.ObjectKeys(ctx.Request.Context(), queries, store, post)
+		if keysErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch media: "+keysErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch media: " + keysErr.Error()))
+			return
+		}
+
+		if err := media.AbortUploads(ctx.Request.Context(), queries, store, post); err != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to abort uploads: "+err.Error())
+			gin.DefaultWriter.Write([]byte("Failed to abort uploads: " + err.Error()))
+			return
+		}
+
+		if err := queries.DeletePost(ctx.Request.Context(), post.ID); err != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to delete post: "+err.Error())
+			gin.DefaultWriter.Write([]byte("Failed to delete post: " + err.Error()))
+			return
+		}
+		// Only published posts were counted in CreateMedia.
+		if post.Status == database.PostStatusPUBLISHED {
+			if err := queries.DecrementPostCount(ctx.Request.Context(), post.UserID); err != nil {
+				gin.DefaultWriter.Write([]byte("Failed to decrement post count: " + err.Error()))
+			}
+		}
+
+		// The post is already gone, so a failure here only leaves orphaned
+		// objects behind and shouldn't fail the request.
+		if err := store.Delete(ctx.Request.Context(), objectKeys); err != nil {
+			gin.DefaultWriter.Write([]byte("Failed to delete post media: " + err.Error()))
+			log.Println("Failed to delete media for post " + post.ID.String() + ": " + err.Error())
+		}
+
+		publisher.PostDeleted(post.ID, groupIDs)
+
+		ctx.JSON(http.StatusOK, gin.H{"success": "Deleted post"})
+	}
+}
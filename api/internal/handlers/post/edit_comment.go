@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/producer"
+	"api/internal/core/utils"
+	"api/internal/middleware"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type EditCommentRequest struct {
+	CommentId uuid.UUID `json:"commentId" binding:"required"`
+	Content   string    `json:"content" binding:"required"`
+}
+
+func EditCommentHandler(queries *database.Queries, publisher *producer.Publisher) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		var editRequest EditCommentRequest
+		if bindErr := ctx.Bind(&editRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+		if !validComment(editRequest.Content) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment"})
+			gin.DefaultWriter.Write([]byte("Invalid comment"))
+			return
+		}
+
+		comment, commentErr := queries.GetComment(ctx.Request.Context(), editRequest.CommentId)
+		if errors.Is(commentErr, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+			gin.DefaultWriter.Write([]byte("Comment not found"))
+			return
+		}
+		if commentErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch comment: "+commentErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch comment: " + commentErr.Error()))
+			return
+		}
+		if comment.UserID != user.ID {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+
+		updateComment := database.UpdateCommentContentParams{
+			ID:          comment.ID,
+			Content:     strings.TrimSpace(editRequest.Content),
+			DateUpdated: utils.PGTime(),
+		}
+		updated, updateErr := queries.UpdateCommentContent(ctx.Request.Context(), updateComment)
+		if updateErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to update comment: "+updateErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to update comment: " + updateErr.Error()))
+			return
+		}
+
+		publisher.CommentUpdated(updated)
+
+		ctx.JSON(http.StatusOK, updated)
+	}
+}
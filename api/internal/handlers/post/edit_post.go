@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/producer"
+	"api/internal/middleware"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type EditPostRequest struct {
+	PostId  uuid.UUID `json:"postId" binding:"required"`
+	Caption *string   `json:"caption"`
+}
+
+// EditPostHandler lets the owner change a post's caption. An empty caption
+// clears it.
+func EditPostHandler(queries *database.Queries, publisher *producer.Publisher) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		var editRequest EditPostRequest
+		if bindErr := ctx.Bind(&editRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+
+		post, postErr := queries.GetPost(ctx.Request.Context(), editRequest.PostId)
+		if errors.Is(postErr, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+			gin.DefaultWriter.Write([]byte("Post not found"))
+			return
+		}
+		if postErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch post: "+postErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch post: " + postErr.Error()))
+			return
+		}
+		if post.UserID != user.ID {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+
+		var caption *string
+		if editRequest.Caption != nil {
+			if trimmed := strings.TrimSpace(*editRequest.Caption); trimmed != "" {
+				caption = &trimmed
+			}
+		}
+		updatePost := database.UpdatePostParams{
+			ID:          post.ID,
+			Media:       post.Media,
+			DateCreated: post.DateCreated,
+			Caption:     caption,
+		}
+		if err := queries.UpdatePost(ctx.Request.Context(), updatePost); err != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to update post: "+err.Error())
+			gin.DefaultWriter.Write([]byte("Failed to update post: " + err.Error()))
+			return
+		}
+		post.Caption = caption
+
+		groupIDs, groupsErr := queries.ListGroupsForPost(ctx.Request.Context(), post.ID)
+		if groupsErr != nil {
+			// The edit went through, clients will pick it up on their next fetch.
+			gin.DefaultWriter.Write([]byte("Failed to fetch post groups: " + groupsErr.Error()))
+		}
+		publisher.PostUpdated(post, groupIDs)
+
+		ctx.JSON(http.StatusOK, post)
+	}
+}
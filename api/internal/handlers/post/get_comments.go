@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/utils"
+	"api/internal/middleware"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// PaginatedCommentsResponse represents the response structure for paginated comments
+type PaginatedCommentsResponse struct {
+	Comments []CommentThread `json:"comments"`
+	Offset   uuid.UUID       `json:"offset,omitempty"`
+	HasMore  bool            `json:"hasMore"`
+}
+
+// CommentThread is a comment plus how many replies it has. Replies always
+// have a ReplyCount of zero since they cannot be replied to.
+type CommentThread struct {
+	Comment    database.Comment `json:"comment"`
+	ReplyCount int64            `json:"replyCount"`
+}
+
+// GetCommentsHandler lists the top level comments on a post in a group, or the
+// replies to a single comment when parentId is set. Comments are returned
+// oldest first and paginated with the last comment ID as the cursor.
+func GetCommentsHandler(queries *database.Queries) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized access: " + tokenErr.Error()))
+			log.Println("Unauthorized access: " + tokenErr.Error())
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			log.Println("Failed to fetch user: " + userErr.Error())
+			return
+		}
+
+		postID, postIdParseErr := uuid.Parse(ctx.Query("postId"))
+		if postIdParseErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid postId"})
+			gin.DefaultWriter.Write([]byte("Invalid postId: " + postIdParseErr.Error()))
+			return
+		}
+		groupID, groupIdParseErr := uuid.Parse(ctx.Query("groupId"))
+		if groupIdParseErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid groupId"})
+			gin.DefaultWriter.Write([]byte("Invalid groupId: " + groupIdParseErr.Error()))
+			return
+		}
+
+		// Check membership
+		checkMembership := database.CheckUserMembershipParams{
+			GroupID: groupID,
+			UserID:  user.ID,
+		}
+		isMember, checkErr := queries.CheckUserMembership(ctx.Request.Context(), checkMembership)
+		if checkErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to check membership: "+checkErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to check membership: " + checkErr.Error()))
+			return
+		}
+		if !isMember {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized access: User is not a member of the group"))
+			return
+		}
+		checkPost := database.CheckPostInGroupParams{
+			GroupID: groupID,
+			PostID:  postID,
+		}
+		inGroup, inGroupErr := queries.CheckPostInGroup(ctx.Request.Context(), checkPost)
+		if inGroupErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to check post: "+inGroupErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to check post: " + inGroupErr.Error()))
+			return
+		}
+		if !inGroup {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Post not found in group"})
+			gin.DefaultWriter.Write([]byte("Post not found in group"))
+			return
+		}
+		if !requireVisiblePost(ctx, queries, postID, user.ID) {
+			return
+		}
+
+		var parentID *uuid.UUID
+		if parentIDStr := ctx.Query("parentId"); parentIDStr != "" {
+			parsed, parseErr := uuid.Parse(parentIDStr)
+			if parseErr != nil {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid parentId"})
+				gin.DefaultWriter.Write([]byte("Invalid parentId: " + parseErr.Error()))
+				return
+			}
+			parent, parentErr := queries.GetComment(ctx.Request.Context(), parsed)
+			if errors.Is(parentErr, pgx.ErrNoRows) || (parentErr == nil && (parent.PostID != postID || parent.GroupID != groupID)) {
+				ctx.JSON(http.StatusNotFound, gin.H{"error": "Parent comment not found"})
+				gin.DefaultWriter.Write([]byte("Parent comment not found"))
+				return
+			}
+			if parentErr != nil {
+				ctx.String(http.StatusInternalServerError, "Failed to fetch parent comment: "+parentErr.Error())
+				gin.DefaultWriter.Write([]byte("Failed to fetch parent comment: " + parentErr.Error()))
+				return
+			}
+			parentID = &parsed
+		}
+
+		// Get pagination parameters
+		limitStr := ctx.DefaultQuery("limit", "20")
+		limit, limitErr := strconv.Atoi(limitStr)
+		if limitErr != nil || limit <= 0 || limit > 50 {
+			limit = 20 // Default to 20 if invalid
+		}
+
+		var threads []CommentThread
+		offsetString := ctx.Query("offset") // Empty if not provided
+		if offsetString != "" {
+			offset, convErr := uuid.Parse(offsetString)
+			if convErr != nil {
+				ctx.String(http.StatusBadRequest, "Invalid cursor")
+				gin.DefaultWriter.Write([]byte("Invalid cursor: " + convErr.Error()))
+				return
+			}
+
+			// Fetch the creation time of the comment the cursor points at
+			cursor, cursorErr := queries.GetComment(ctx.Request.Context(), offset)
+			if cursorErr != nil {
+				ctx.String(http.StatusBadRequest, "Invalid cursor")
+				gin.DefaultWriter.Write([]byte("Invalid cursor: " + cursorErr.Error()))
+				return
+			}
+
+			if parentID != nil {
+				params := database.ListPaginatedRepliesForCommentParams{
+					Column1:     *parentID,
+					DateCreated: cursor.DateCreated,
+					Column3:     offset,
+					Limit:       int32(limit + 1),
+				}
+				replies, listErr := queries.ListPaginatedRepliesForComment(ctx.Request.Context(), params)
+				if listErr != nil {
+					ctx.String(http.StatusInternalServerError, "Error: Failed to retrieve comments: "+listErr.Error())
+					gin.DefaultWriter.Write([]byte("Error: Failed to retrieve comments: " + listErr.Error()))
+					return
+				}
+				threads = repliesToThreads(replies)
+			} else {
+				params := database.ListPaginatedCommentsForPostParams{
+					GroupID:     groupID,
+					PostID:      postID,
+					DateCreated: cursor.DateCreated,
+					Column4:     offset,
+					Limit:       int32(limit + 1),
+				}
+				rows, listErr := queries.ListPaginatedCommentsForPost(ctx.Request.Context(), params)
+				if listErr != nil {
+					ctx.String(http.StatusInternalServerError, "Error: Failed to retrieve comments: "+listErr.Error())
+					gin.DefaultWriter.Write([]byte("Error: Failed to retrieve comments: " + listErr.Error()))
+					return
+				}
+				threads = make([]CommentThread, 0, len(rows))
+				for _, row := range rows {
+					threads = append(threads, CommentThread{Comment: row.Comment, ReplyCount: row.ReplyCount})
+				}
+			}
+		} else {
+			// Initial fetch without offset
+			if parentID != nil {
+				params := database.InitialRepliesForCommentParams{
+					Column1: *parentID,
+					Limit:   int32(limit + 1),
+				}
+				replies, initialErr := queries.InitialRepliesForComment(ctx.Request.Context(), params)
+				if initialErr != nil {
+					ctx.String(http.StatusInternalServerError, "Error: Failed to retrieve comments: "+initialErr.Error())
+					gin.DefaultWriter.Write([]byte("Error: Failed to retrieve comments: " + initialErr.Error()))
+					return
+				}
+				threads = repliesToThreads(replies)
+			} else {
+				params := database.InitialCommentsForPostParams{
+					GroupID: groupID,
+					PostID:  postID,
+					Limit:   int32(limit + 1),
+				}
+				rows, initialErr := queries.InitialCommentsForPost(ctx.Request.Context(), params)
+				if initialErr != nil {
+					ctx.String(http.StatusInternalServerError, "Error: Failed to retrieve comments: "+initialErr.Error())
+					gin.DefaultWriter.Write([]byte("Error: Failed to retrieve comments: " + initialErr.Error()))
+					return
+				}
+				threads = make([]CommentThread, 0, len(rows))
+				for _, row := range rows {
+					threads = append(threads, CommentThread{Comment: row.Comment, ReplyCount: row.ReplyCount})
+				}
+			}
+		}
+
+		// Check if there are more comments
+		hasMore := false
+		if len(threads) > limit {
+			hasMore = true
+			threads = threads[:limit] // Remove the extra comment
+		}
+
+		response := PaginatedCommentsResponse{
+			Comments: threads,
+			HasMore:  hasMore,
+		}
+		// Only include the cursor if there are more comments
+		if hasMore {
+			response.Offset = threads[len(threads)-1].Comment.ID
+		}
+
+		responseJSON, err := json.Marshal(response)
+		if err != nil {
+			ctx.String(http.StatusInternalServerError, "Error generating response")
+			gin.DefaultWriter.Write([]byte("Error generating response: " + err.Error()))
+			return
+		}
+
+		// Comments change often, so always revalidate against the ETag.
+		if handled := utils.AttachCacheHeaders(ctx, responseJSON, 0); handled {
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response)
+	}
+}
+
+func repliesToThreads(replies []database.Comment) []CommentThread {
+	threads := make([]CommentThread, 0, len(replies))
+	for _, reply := range replies {
+		threads = append(threads, CommentThread{Comment: reply})
+	}
+	return threads
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"api/internal/core/message"
+	"api/internal/core/utils"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetEventSchemasHandler returns the JSON Schema of the data of every realtime
+// event type, keyed by type.
+func GetEventSchemasHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		schemas := message.JSONSchemas()
+		responseJSON, err := json.Marshal(schemas)
+		if err != nil {
+			ctx.String(http.StatusInternalServerError, "Error generating response")
+			gin.DefaultWriter.Write([]byte("Error generating response: " + err.Error()))
+			return
+		}
+
+		// Schemas only change with a deploy.
+		if handled := utils.AttachCacheHeaders(ctx, responseJSON, 3600); handled {
+			return
+		}
+
+		ctx.JSON(http.StatusOK, schemas)
+	}
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/fetch"
+	"api/internal/core/utils"
+	"api/internal/middleware"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// PaginatedFeedResponse represents the response structure for the home feed.
+// Offset is an opaque cursor holding the (score, post_id) of the last post.
+type PaginatedFeedResponse struct {
+	Posts   []FeedPost `json:"posts"`
+	Offset  string     `json:"offset,omitempty"`
+	HasMore bool       `json:"hasMore"`
+}
+
+// FeedPost is a post with its media and the user's groups it was shared to.
+type FeedPost struct {
+	PostWithMedia
+	Groups []uuid.UUID `json:"groups"`
+}
+
+type feedRow struct {
+	post   database.Post
+	score  pgtype.Numeric
+	groups []uuid.UUID
+}
+
+// GetFeedHandler merges the posts of every group the user belongs to. A post
+// shared to several groups appears once, ranked by its best score.
+func GetFeedHandler(queries *database.Queries) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized access: " + tokenErr.Error()))
+			log.Println("Unauthorized access: " + tokenErr.Error())
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			log.Println("Failed to fetch user: " + userErr.Error())
+			return
+		}
+
+		userGroups, groupErr := queries.ListUserGroups(ctx.Request.Context(), user.ID)
+		if groupErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch groups: "+groupErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch groups: " + groupErr.Error()))
+			log.Println("Failed to fetch groups: " + groupErr.Error())
+			return
+		}
+		groupIDs := make([]uuid.UUID, 0, len(userGroups))
+		for _, group := range userGroups {
+			groupIDs = append(groupIDs, group.FriendGroup.ID)
+		}
+
+		// Get pagination parameters
+		limitStr := ctx.DefaultQuery("limit", "10")
+		limit, limitErr := strconv.Atoi(limitStr)
+		if limitErr != nil || limit <= 0 || limit > 20 {
+			limit = 10 // Default to 10 if invalid
+		}
+
+		var rows []feedRow
+		offsetString := ctx.Query("offset") // Empty if not provided
+		if offsetString != "" {
+			score, offset, cursorErr := decodeFeedCursor(offsetString)
+			if cursorErr != nil {
+				ctx.String(http.StatusBadRequest, "Invalid cursor")
+				gin.DefaultWriter.Write([]byte("Invalid cursor: " + cursorErr.Error()))
+				log.Println("Invalid cursor: " + cursorErr.Error())
+				return
+			}
+			params := database.ListPaginatedFeedForGroupsParams{
+				Column1:  groupIDs,
+				Column2:  score,
+				Column3:  offset,
+				Limit:    int32(limit + 1),
+				FriendID: user.ID,
+			}
+			feed, listErr := queries.ListPaginatedFeedForGroups(ctx.Request.Context(), params)
+			if listErr != nil {
+				ctx.String(http.StatusInternalServerError, "Error: Failed to retrieve posts: "+listErr.Error())
+				gin.DefaultWriter.Write([]byte("Error: Failed to retrieve posts: " + listErr.Error()))
+				log.Println("Error: Failed to retrieve posts: " + listErr.Error())
+				return
+			}
+			rows = make([]feedRow, 0, len(feed))
+			for _, row := range feed {
+				rows = append(rows, feedRow{post: row.Post, score: row.Score, groups: row.Groups})
+			}
+		} else {
+			// Initial fetch without offset
+			params := database.InitialFeedForGroupsParams{
+				Column1:  groupIDs,
+				Limit:    int32(limit + 1),
+				FriendID: user.ID,
+			}
+			feed, initialErr := queries.InitialFeedForGroups(ctx.Request.Context(), params)
+			if initialErr != nil {
+				ctx.String(http.StatusInternalServerError, "Error: Failed to retrieve posts: "+initialErr.Error())
+				gin.DefaultWriter.Write([]byte("Error: Failed to retrieve posts: " + initialErr.Error()))
+				log.Println("Error: Failed to retrieve posts: " + initialErr.Error())
+				return
+			}
+			rows = make([]feedRow, 0, len(feed))
+			for _, row := range feed {
+				rows = append(rows, feedRow{post: row.Post, score: row.Score, groups: row.Groups})
+			}
+		}
+
+		// Check if there are more posts
+		hasMore := false
+		if len(rows) > limit {
+			hasMore = true
+			rows = rows[:limit] // Remove the extra post
+		}
+
+		postIDs := make([]uuid.UUID, 0, len(rows))
+		for _, row := range rows {
+			postIDs = append(postIDs, row.post.ID)
+		}
+		reactions, reactionsErr := fetch.FetchReactionCounts(ctx.Request.Context(), queries, postIDs)
+		if reactionsErr != nil {
+			ctx.String(http.StatusInternalServerError, "Error: Failed to retrieve reactions: "+reactionsErr.Error())
+			gin.DefaultWriter.Write([]byte("Error: Failed to retrieve reactions: " + reactionsErr.Error()))
+			log.Println("Error: Failed to retrieve reactions: " + reactionsErr.Error())
+			return
+		}
+
+		postList := make([]database.Post, 0, len(rows))
+		for _, row := range rows {
+			postList = append(postList, row.post)
+		}
+		media, mediaErr := fetch.FetchMediaForPosts(ctx.Request.Context(), queries, postList)
+		if mediaErr != nil {
+			ctx.String(http.StatusInternalServerError, "Error: Failed to retrieve media: "+mediaErr.Error())
+			gin.DefaultWriter.Write([]byte("Error: Failed to retrieve media: " + mediaErr.Error()))
+			log.Println("Error: Failed to retrieve media: " + mediaErr.Error())
+			return
+		}
+
+		posts := make([]FeedPost, 0, len(rows))
+		for _, row := range rows {
+			posts = append(posts, FeedPost{
+				PostWithMedia: PostWithMedia{
+					Post:      row.post,
+					Media:     media[row.post.ID],
+					Reactions: reactions[row.post.ID],
+				},
+				Groups: row.groups,
+			})
+		}
+
+		response := PaginatedFeedResponse{
+			Posts:   posts,
+			HasMore: hasMore,
+		}
+		// Only include the cursor if there are more posts
+		if hasMore {
+			last := rows[len(rows)-1]
+			cursor, cursorErr := encodeFeedCursor(last.score, last.post.ID)
+			if cursorErr != nil {
+				ctx.String(http.StatusInternalServerError, "Error generating response")
+				gin.DefaultWriter.Write([]byte("Error generating cursor: " + cursorErr.Error()))
+				log.Println("Error generating cursor: " + cursorErr.Error())
+				return
+			}
+			response.Offset = cursor
+		}
+
+		responseJSON, err := json.Marshal(response)
+		if err != nil {
+			ctx.String(http.StatusInternalServerError, "Error generating response")
+			gin.DefaultWriter.Write([]byte("Error generating response: " + err.Error()))
+			log.Println("Error generating response: " + err.Error())
+			return
+		}
+
+		if handled := utils.AttachCacheHeaders(ctx, responseJSON, 30); handled {
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response)
+	}
+}
+
+// encodeFeedCursor packs the exact decimal score together with the post ID so
+// the next page continues from the same (score, post_id) tuple even if the
+// post is rescored in the meantime.
+func encodeFeedCursor(score pgtype.Numeric, postID uuid.UUID) (string, error) {
+	value, err := score.Value()
+	if err != nil {
+		return "", err
+	}
+	scoreString, ok := value.(string)
+	if !ok {
+		return "", errors.New("score is not a finite number")
+	}
+	raw := scoreString + "|" + postID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw)), nil
+}
+
+func decodeFeedCursor(cursor string) (pgtype.Numeric, uuid.UUID, error) {
+	var score pgtype.Numeric
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return score, uuid.Nil, err
+	}
+	scoreString, postIDString, found := strings.Cut(string(raw), "|")
+	if !found {
+		return score, uuid.Nil, fmt.Errorf("malformed cursor %q", cursor)
+	}
+	if err := score.Scan(scoreString); err != nil {
+		return score, uuid.Nil, err
+	}
+	postID, err := uuid.Parse(postIDString)
+	if err != nil {
+		return score, uuid.Nil, err
+	}
+	return score, postID, nil
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestFeedCursorRoundTrip(t *testing.T) {
+	// Postgres orders NaN above every number, so it is a valid position too.
+	tests := []string{"0", "1.5", "-2.25", "0.000000000123456789", "123456789012345678901234567890.5", "NaN"}
+	for _, scoreString := range tests {
+		t.Run(scoreString, func(t *testing.T) {
+			var score pgtype.Numeric
+			if err := score.Scan(scoreString); err != nil {
+				t.Fatalf("Scan(%q) = %v", scoreString, err)
+			}
+			postID := uuid.New()
+
+			cursor, err := encodeFeedCursor(score, postID)
+			if err != nil {
+				t.Fatalf("encodeFeedCursor() = %v", err)
+			}
+			gotScore, gotPostID, err := decodeFeedCursor(cursor)
+			if err != nil {
+				t.Fatalf("decodeFeedCursor(%q) = %v", cursor, err)
+			}
+			if gotPostID != postID {
+				t.Errorf("post ID = %s, want %s", gotPostID, postID)
+			}
+			want, _ := score.Value()
+			got, _ := gotScore.Value()
+			if got != want {
+				t.Errorf("score = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestEncodeFeedCursorRejectsNull(t *testing.T) {
+	if cursor, err := encodeFeedCursor(pgtype.Numeric{}, uuid.New()); err == nil {
+		t.Errorf("encodeFeedCursor() = %q, want an error", cursor)
+	}
+}
+
+func TestDecodeFeedCursorRejectsMalformed(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not base64", cursor: "!!!"},
+		{name: "no separator", cursor: encode("1.5")},
+		{name: "bad score", cursor: encode("high|" + uuid.NewString())},
+		{name: "bad post ID", cursor: encode("1.5|post")},
+		{name: "empty", cursor: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, _, err := decodeFeedCursor(test.cursor); err == nil {
+				t.Errorf("decodeFeedCursor(%q) succeeded, want an error", test.cursor)
+			}
+		})
+	}
+}
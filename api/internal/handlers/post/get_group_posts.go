@@ -23,8 +23,9 @@ type PaginatedPostsResponse struct {
 
 // PostWithMedia combines post metadata with its associated media
 type PostWithMedia struct {
-	Post  database.Post `json:"post"`
-	Media any           `json:"media,omitempty"`
+	Post      database.Post         `json:"post"`
+	Media     any                   `json:"media,omitempty"`
+	Reactions []fetch.ReactionCount `json:"reactions"`
 }
 
 // GetGroupPostsHandler handles fetching paginated posts with media for a group
@@ -118,10 +119,11 @@ func GetGroupPostsHandler(queries *database.Queries) gin.HandlerFunc {
 			}
 
 			params := database.ListPaginatedPostsForGroupParams{
-				GroupID: groupID,
-				Score:   score,
-				Column3: offset,
-				Limit:   int32(limit + 1),
+				GroupID:  groupID,
+				Score:    score,
+				Column3:  offset,
+				Limit:    int32(limit + 1),
+				FriendID: user.ID,
 			}
 
 			rows, listPaginatedErr := queries.ListPaginatedPostsForGroup(ctx.Request.Context(), params)
@@ -138,8 +140,9 @@ func GetGroupPostsHandler(queries *database.Queries) gin.HandlerFunc {
 		} else {
 			// Initial fetch without offset
 			params := database.InitialPostsForGroupParams{
-				GroupID: groupID,
-				Limit:   int32(limit + 1),
+				GroupID:  groupID,
+				Limit:    int32(limit + 1),
+				FriendID: user.ID,
 			}
 			rows, initalErr := queries.InitialPostsForGroup(ctx.Request.Context(), params)
 			if initalErr != nil {
@@ -161,7 +164,27 @@ func GetGroupPostsHandler(queries *database.Queries) gin.HandlerFunc {
 			postLists = postLists[:limit] // Remove the extra post
 		}
 
-		// Process posts and fetch media
+		// Fetch reactions and media for the whole page at once
+		postIDs := make([]uuid.UUID, 0, len(postLists))
+		for _, post := range postLists {
+			postIDs = append(postIDs, post.ID)
+		}
+		reactions, reactionsErr := fetch.FetchReactionCounts(ctx.Request.Context(), queries, postIDs)
+		if reactionsErr != nil {
+			ctx.String(http.StatusInternalServerError, "Error: Failed to retrieve reactions: "+reactionsErr.Error())
+			gin.DefaultWriter.Write([]byte("Error: Failed to retrieve reactions: " + reactionsErr.Error()))
+			log.Println("Error: Failed to retrieve reactions: " + reactionsErr.Error())
+			return
+		}
+		media, mediaErr := fetch.FetchMediaForPosts(ctx.Request.Context(), queries, postLists)
+		if mediaErr != nil {
+			ctx.String(http.StatusInternalServerError, "Error: Failed to retrieve media: "+mediaErr.Error())
+			gin.DefaultWriter.Write([]byte("Error: Failed to retrieve media: " + mediaErr.Error()))
+			log.Println("Error: Failed to retrieve media: " + mediaErr.Error())
+			return
+		}
+
+		// Attach media and reactions to each post
 		postsWithMedia := make([]PostWithMedia, 0, len(postLists))
 		var nextOffset uuid.UUID
 
@@ -171,13 +194,10 @@ func GetGroupPostsHandler(queries *database.Queries) gin.HandlerFunc {
 				nextOffset = post.ID
 			}
 
-			// Fetch media for the post
-			var media any
-
-			media = fetch.FetchMedia(ctx.Request.Context(), queries, post)
 			postsWithMedia = append(postsWithMedia, PostWithMedia{
-				Post:  post,
-				Media: media,
+				Post:      post,
+				Media:     media[post.ID],
+				Reactions: reactions[post.ID],
 			})
 		}
 
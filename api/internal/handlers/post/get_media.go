@@ -2,6 +2,7 @@ package handlers
 
 import (
 	database "api/internal/core/db"
+	"api/internal/core/fetch"
 	"api/internal/core/utils"
 	"api/internal/middleware"
 	"encoding/json"
@@ -57,6 +58,9 @@ func GetMediaHandler(queries *database.Queries) gin.HandlerFunc {
 			gin.DefaultWriter.Write([]byte("Unauthorized"))
 			return
 		}
+		if !requireVisiblePost(ctx, queries, postID, user.ID) {
+			return
+		}
 		var media any
 		var mediaErr error
 		switch mediaStr {
@@ -68,6 +72,8 @@ func GetMediaHandler(queries *database.Queries) gin.HandlerFunc {
 			media, mediaErr = queries.GetLinks(ctx.Request.Context(), postID)
 		case database.MediaTypeTEXT:
 			media, mediaErr = queries.GetTexts(ctx.Request.Context(), postID)
+		case database.MediaTypeCAROUSEL:
+			media, mediaErr = fetch.FetchCarouselItems(ctx.Request.Context(), queries, postID)
 		}
 		if mediaErr != nil {
 			ctx.String(http.StatusInternalServerError, "Failed to fetch media: "+mediaErr.Error())
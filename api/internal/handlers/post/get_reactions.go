@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/fetch"
+	"api/internal/core/utils"
+	"api/internal/middleware"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type GetReactionsResponse struct {
+	Counts    []fetch.ReactionCount   `json:"counts"`
+	Reactions []database.PostReaction `json:"reactions"`
+}
+
+func GetReactionsHandler(queries *database.Queries) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		postIDStr := ctx.Query("postId")
+		if postIDStr == "" {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "postId is required"})
+			gin.DefaultWriter.Write([]byte("Failed to query postId"))
+			return
+		}
+		postID, err := uuid.Parse(postIDStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid postId"})
+			gin.DefaultWriter.Write([]byte("Failed to parse postId"))
+			return
+		}
+
+		checkMembership := database.CheckUserMemberOfPostGroupsParams{
+			UserID: user.ID,
+			PostID: postID,
+		}
+		isMember, checkErr := queries.CheckUserMemberOfPostGroups(ctx.Request.Context(), checkMembership)
+		if checkErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to check membership: "+checkErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to check membership: " + checkErr.Error()))
+			return
+		}
+		if !isMember {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		if !requireVisiblePost(ctx, queries, postID, user.ID) {
+			return
+		}
+
+		reactions, listErr := queries.ListPostReactions(ctx.Request.Context(), postID)
+		if listErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch reactions: "+listErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch reactions: " + listErr.Error()))
+			return
+		}
+		counts, countErr := fetch.FetchReactionCounts(ctx.Request.Context(), queries, []uuid.UUID{postID})
+		if countErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch reactions: "+countErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch reactions: " + countErr.Error()))
+			return
+		}
+
+		// Empty lists are sent as [] rather than null.
+		response := GetReactionsResponse{
+			Counts:    counts[postID],
+			Reactions: reactions,
+		}
+		if response.Counts == nil {
+			response.Counts = []fetch.ReactionCount{}
+		}
+		if response.Reactions == nil {
+			response.Reactions = []database.PostReaction{}
+		}
+
+		responseJSON, err := json.Marshal(response)
+		if err != nil {
+			ctx.String(http.StatusInternalServerError, "Error generating response")
+			return
+		}
+
+		if handled := utils.AttachCacheHeaders(ctx, responseJSON, 30); handled {
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response)
+	}
+}
@@ -49,16 +49,30 @@ func GetTopPostHandler(queries *database.Queries) gin.HandlerFunc {
 			ctx.String(http.StatusUnauthorized, "Unauthorized")
 			return
 		}
-		postRow, fetchErr := queries.GetTopPost(ctx.Request.Context(), groupID)
+		getTopPost := database.GetTopPostParams{
+			GroupID:  groupID,
+			FriendID: user.ID,
+		}
+		postRow, fetchErr := queries.GetTopPost(ctx.Request.Context(), getTopPost)
 		if fetchErr != nil {
 			ctx.String(http.StatusInternalServerError, "Failed to fetch post: "+fetchErr.Error())
 			return
 		}
-		media := fetch.FetchMedia(ctx.Request.Context(), queries, postRow.Post)
+		media, mediaErr := fetch.FetchMediaForPosts(ctx.Request.Context(), queries, []database.Post{postRow.Post})
+		if mediaErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch media: "+mediaErr.Error())
+			return
+		}
+		reactions, reactionsErr := fetch.FetchReactionCounts(ctx.Request.Context(), queries, []uuid.UUID{postRow.Post.ID})
+		if reactionsErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch reactions: "+reactionsErr.Error())
+			return
+		}
 
 		response := PostWithMedia{
-			Post:  postRow.Post,
-			Media: media,
+			Post:      postRow.Post,
+			Media:     media[postRow.Post.ID],
+			Reactions: reactions[postRow.Post.ID],
 		}
 
 		responseJSON, err := json.Marshal(response)
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/media"
+	"api/internal/core/storage"
+	"api/internal/middleware"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// UploadProgressResponse reports which parts of a resumable upload are in
+// place. MissingParts lists the parts still to upload, including any whose
+// size came out wrong and has to be uploaded again.
+type UploadProgressResponse struct {
+	SlotId        uuid.UUID      `json:"slotId"`
+	Size          int64          `json:"size"`
+	PartSize      int64          `json:"partSize"`
+	PartCount     int32          `json:"partCount"`
+	UploadedBytes int64          `json:"uploadedBytes"`
+	UploadedParts []storage.Part `json:"uploadedParts"`
+	MissingParts  []int32        `json:"missingParts"`
+	Assembled     bool           `json:"assembled"`
+}
+
+func GetUploadProgressHandler(queries *database.Queries, store storage.Storage) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		multipart, canResume := store.(storage.MultipartUploader)
+		if !canResume {
+			ctx.JSON(http.StatusNotImplemented, gin.H{"error": "Storage does not support resumable uploads"})
+			gin.DefaultWriter.Write([]byte("Storage does not support resumable uploads"))
+			return
+		}
+
+		slotID, slotIdParseErr := uuid.Parse(ctx.Query("slotId"))
+		if slotIdParseErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid slotId"})
+			gin.DefaultWriter.Write([]byte("Invalid slotId: " + slotIdParseErr.Error()))
+			return
+		}
+		slot, found := ownedUploadSlot(ctx, queries, user.ID, slotID)
+		if !found {
+			return
+		}
+		if slot.PartSize == nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Upload is not resumable"})
+			gin.DefaultWriter.Write([]byte("Upload is not resumable"))
+			return
+		}
+
+		progress := UploadProgressResponse{
+			SlotId:        slot.ID,
+			Size:          slot.Size,
+			PartSize:      *slot.PartSize,
+			PartCount:     media.PartCount(slot.Size, *slot.PartSize),
+			UploadedParts: []storage.Part{},
+			MissingParts:  []int32{},
+		}
+		if slot.UploadID == nil {
+			progress.UploadedBytes = slot.Size
+			progress.Assembled = true
+			ctx.JSON(http.StatusOK, progress)
+			return
+		}
+
+		parts, listErr := multipart.ListParts(ctx.Request.Context(), slot.ObjectKey, *slot.UploadID)
+		if errors.Is(listErr, storage.ErrNoSuchUpload) {
+			ctx.JSON(http.StatusGone, gin.H{"error": "Upload expired"})
+			gin.DefaultWriter.Write([]byte("Upload expired: " + slot.ID.String()))
+			return
+		}
+		if listErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch upload parts: "+listErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to list parts: " + listErr.Error()))
+			return
+		}
+		complete := completeParts(slot, parts)
+		for number := int32(1); number <= progress.PartCount; number++ {
+			part, ok := complete[number]
+			if !ok {
+				progress.MissingParts = append(progress.MissingParts, number)
+				continue
+			}
+			progress.UploadedParts = append(progress.UploadedParts, part)
+			progress.UploadedBytes += part.Size
+		}
+
+		ctx.JSON(http.StatusOK, progress)
+	}
+}
+
+// completeParts keys the uploaded parts that have the size they should by
+// their number.
+func completeParts(slot database.UploadSlot, parts []storage.Part) map[int32]storage.Part {
+	complete := make(map[int32]storage.Part, len(parts))
+	for _, part := range parts {
+		if part.Size == media.ExpectedPartSize(slot.Size, *slot.PartSize, part.Number) {
+			complete[part.Number] = part
+		}
+	}
+	return complete
+}
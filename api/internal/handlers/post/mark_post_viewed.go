@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/utils"
+	"api/internal/middleware"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type MarkPostViewedRequest struct {
+	PostId uuid.UUID `json:"postId" binding:"required"`
+}
+
+func MarkPostViewedHandler(queries *database.Queries) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		var viewRequest MarkPostViewedRequest
+		if bindErr := ctx.Bind(&viewRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+
+		checkMembership := database.CheckUserMemberOfPostGroupsParams{
+			UserID: user.ID,
+			PostID: viewRequest.PostId,
+		}
+		isMember, checkErr := queries.CheckUserMemberOfPostGroups(ctx.Request.Context(), checkMembership)
+		if checkErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to check membership: "+checkErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to check membership: " + checkErr.Error()))
+			return
+		}
+		if !isMember {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+
+		recordView := database.RecordPostViewParams{
+			PostID:     viewRequest.PostId,
+			UserID:     user.ID,
+			DateViewed: utils.PGTime(),
+		}
+		if err := queries.RecordPostView(ctx.Request.Context(), recordView); err != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to record view: "+err.Error())
+			gin.DefaultWriter.Write([]byte("Failed to record view: " + err.Error()))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"success": "Recorded view"})
+	}
+}
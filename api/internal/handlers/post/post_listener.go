@@ -12,7 +12,6 @@ import (
 
 func PostListenerHandler(queries *database.Queries, hub *message.Hub) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		// Extract the target user ID from the URL parameters.
 		token, tokenErr := middleware.GetAuthToken(ctx)
 		if tokenErr != nil {
 			ctx.String(http.StatusUnauthorized, "Unauthorized")
@@ -26,37 +25,20 @@ func PostListenerHandler(queries *database.Queries, hub *message.Hub) gin.Handle
 			return
 		}
 
-		groupIDStr := ctx.Query("groupId")
-		if groupIDStr == "" {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "groupId is required"})
-			gin.DefaultWriter.Write([]byte("Failed to query groupId"))
+		// Subscribe the socket to every group the user belongs to.
+		userGroups, groupErr := queries.ListUserGroups(ctx.Request.Context(), user.ID)
+		if groupErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch groups: "+groupErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch groups: " + groupErr.Error()))
 			return
 		}
-
-		groupID, err := uuid.Parse(groupIDStr)
-		if err != nil {
-			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid groupId"})
-			gin.DefaultWriter.Write([]byte("Failed to parse groupId"))
-			return
-		}
-		checkMembership := database.CheckUserMembershipParams{
-			GroupID: groupID,
-			UserID:  user.ID,
-		}
-		isMember, checkErr := queries.CheckUserMembership(ctx.Request.Context(), checkMembership)
-		if checkErr != nil {
-			ctx.String(http.StatusInternalServerError, "Failed to check membership: "+checkErr.Error())
-			gin.DefaultWriter.Write([]byte("Failed to check membership: " + checkErr.Error()))
-			return
-		}
-		if !isMember {
-			ctx.String(http.StatusUnauthorized, "Unauthorized")
-			gin.DefaultWriter.Write([]byte("Unauthorized"))
-			return
+		groupIDs := make([]uuid.UUID, 0, len(userGroups))
+		for _, group := range userGroups {
+			groupIDs = append(groupIDs, group.FriendGroup.ID)
 		}
 
 		// Upgrade the HTTP connection to a WebSocket.
 		// This registers the client with the Hub and starts its read/write pumps.
-		message.ServeWS(hub, ctx.Writer, ctx.Request, groupID)
+		message.ServeWS(hub, ctx.Writer, ctx.Request, user.ID, groupIDs)
 	}
 }
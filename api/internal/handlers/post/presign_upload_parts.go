@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/media"
+	"api/internal/core/storage"
+	"api/internal/middleware"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxPartsPerRequest bounds how many part URLs are signed at once.
+const maxPartsPerRequest = 100
+
+type PresignUploadPartsRequest struct {
+	SlotId uuid.UUID `json:"slotId" binding:"required"`
+	Parts  []int32   `json:"parts" binding:"required"`
+}
+
+type UploadPartResponse struct {
+	Number int32                    `json:"number"`
+	Upload storage.PresignedRequest `json:"upload"`
+}
+
+// PresignUploadPartsHandler signs URLs for parts of a resumable upload. A
+// part that failed is retried by asking for its URL again. Each call keeps
+// the upload from being cleaned up as abandoned.
+func PresignUploadPartsHandler(queries *database.Queries, store storage.Storage) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		multipart, canResume := store.(storage.MultipartUploader)
+		if !canResume {
+			ctx.JSON(http.StatusNotImplemented, gin.H{"error": "Storage does not support resumable uploads"})
+			gin.DefaultWriter.Write([]byte("Storage does not support resumable uploads"))
+			return
+		}
+
+		var partsRequest PresignUploadPartsRequest
+		if bindErr := ctx.Bind(&partsRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+		if len(partsRequest.Parts) == 0 || len(partsRequest.Parts) > maxPartsPerRequest {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid number of parts"})
+			gin.DefaultWriter.Write([]byte("Invalid number of parts"))
+			return
+		}
+
+		slot, found := ownedUploadSlot(ctx, queries, user.ID, partsRequest.SlotId)
+		if !found {
+			return
+		}
+		if slot.UploadID == nil || slot.PartSize == nil {
+			ctx.JSON(http.StatusConflict, gin.H{"error": "Upload is not resumable or already assembled"})
+			gin.DefaultWriter.Write([]byte("Upload is not resumable or already assembled"))
+			return
+		}
+
+		partCount := media.PartCount(slot.Size, *slot.PartSize)
+		for _, number := range partsRequest.Parts {
+			if number < 1 || number > partCount {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid part " + strconv.Itoa(int(number))})
+				gin.DefaultWriter.Write([]byte("Invalid part " + strconv.Itoa(int(number))))
+				return
+			}
+		}
+
+		expiresAt := time.Now().Add(media.ResumableUploadExpiry)
+		touch := database.TouchUploadSlotParams{
+			ID:        slot.ID,
+			ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+		}
+		if touchErr := queries.TouchUploadSlot(ctx.Request.Context(), touch); touchErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to update upload: "+touchErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to touch upload slot: " + touchErr.Error()))
+			return
+		}
+
+		parts := make([]UploadPartResponse, 0, len(partsRequest.Parts))
+		for _, number := range partsRequest.Parts {
+			size := media.ExpectedPartSize(slot.Size, *slot.PartSize, number)
+			upload, presignErr := multipart.PresignUploadPart(ctx.Request.Context(), slot.ObjectKey, *slot.UploadID, number, size, uploadURLExpiry)
+			if presignErr != nil {
+				ctx.String(http.StatusInternalServerError, "Failed to sign part: "+presignErr.Error())
+				gin.DefaultWriter.Write([]byte("Failed to presign part: " + presignErr.Error()))
+				return
+			}
+			parts = append(parts, UploadPartResponse{Number: number, Upload: upload})
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"parts": parts, "expiresAt": expiresAt})
+	}
+}
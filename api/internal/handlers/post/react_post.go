@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/producer"
+	"api/internal/core/utils"
+	"api/internal/middleware"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Reactions are short emoji strings; anything longer than this is rejected.
+const maxReactionLength = 8
+
+type ReactionRequest struct {
+	PostId   uuid.UUID `json:"postId" binding:"required"`
+	Reaction string    `json:"reaction" binding:"required"`
+}
+
+func validReaction(reaction string) bool {
+	reaction = strings.TrimSpace(reaction)
+	return reaction != "" && utf8.RuneCountInString(reaction) <= maxReactionLength
+}
+
+func ReactPostHandler(queries *database.Queries, publisher *producer.Publisher) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		var reactRequest ReactionRequest
+		if bindErr := ctx.Bind(&reactRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+		if !validReaction(reactRequest.Reaction) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reaction"})
+			gin.DefaultWriter.Write([]byte("Invalid reaction: " + reactRequest.Reaction))
+			return
+		}
+
+		checkMembership := database.CheckUserMemberOfPostGroupsParams{
+			UserID: user.ID,
+			PostID: reactRequest.PostId,
+		}
+		isMember, checkErr := queries.CheckUserMemberOfPostGroups(ctx.Request.Context(), checkMembership)
+		if checkErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to check membership: "+checkErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to check membership: " + checkErr.Error()))
+			return
+		}
+		if !isMember {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		if !requireVisiblePost(ctx, queries, reactRequest.PostId, user.ID) {
+			return
+		}
+
+		addReaction := database.AddReactionParams{
+			PostID:      reactRequest.PostId,
+			UserID:      user.ID,
+			Reaction:    strings.TrimSpace(reactRequest.Reaction),
+			DateCreated: utils.PGTime(),
+		}
+		added, err := queries.AddReaction(ctx.Request.Context(), addReaction)
+		if err != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to add reaction: "+err.Error())
+			gin.DefaultWriter.Write([]byte("Failed to add reaction: " + err.Error()))
+			return
+		}
+		if added == 0 {
+			// The user already reacted with this, so there is nothing new
+			// to announce.
+			ctx.JSON(http.StatusOK, gin.H{"success": "Added reaction"})
+			return
+		}
+
+		groupIDs, groupsErr := queries.ListGroupsForPost(ctx.Request.Context(), addReaction.PostID)
+		if groupsErr != nil {
+			gin.DefaultWriter.Write([]byte("Failed to fetch post groups: " + groupsErr.Error()))
+		}
+		reaction := producer.Reaction{
+			PostID:   addReaction.PostID,
+			UserID:   addReaction.UserID,
+			Reaction: addReaction.Reaction,
+		}
+		publisher.ReactionAdded(reaction, groupIDs)
+
+		ctx.JSON(http.StatusOK, gin.H{"success": "Added reaction"})
+	}
+}
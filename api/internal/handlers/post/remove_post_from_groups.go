@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/producer"
+	"api/internal/middleware"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RemovePostFromGroupsRequest struct {
+	PostId   uuid.UUID   `json:"postId" binding:"required"`
+	GroupIds []uuid.UUID `json:"groupIds" binding:"required,min=1"`
+}
+
+// RemovePostFromGroupsHandler pulls a post out of some of the groups it was
+// shared to. The post itself and its media are kept.
+func RemovePostFromGroupsHandler(queries *database.Queries, publisher *producer.Publisher) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		var removeRequest RemovePostFromGroupsRequest
+		if bindErr := ctx.Bind(&removeRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+
+		checkPost := database.CheckPostOwnerParams{
+			UserID: user.ID,
+			ID:     removeRequest.PostId,
+		}
+		isOwner, checkErr := queries.CheckPostOwner(ctx.Request.Context(), checkPost)
+		if checkErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to check post ownership: "+checkErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to check post ownership: " + checkErr.Error()))
+			return
+		}
+		if !isOwner {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+
+		for _, groupID := range removeRequest.GroupIds {
+			removePost := database.RemovePostFromFriendGroupParams{
+				GroupID: groupID,
+				PostID:  removeRequest.PostId,
+			}
+			if err := queries.RemovePostFromFriendGroup(ctx.Request.Context(), removePost); err != nil {
+				ctx.String(http.StatusInternalServerError, "Failed to remove post from group: "+err.Error())
+				gin.DefaultWriter.Write([]byte("Failed to remove post from group: " + err.Error()))
+				return
+			}
+			publisher.PostRemoved(removeRequest.PostId, []uuid.UUID{groupID})
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"success": "Removed post from groups"})
+	}
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/message"
+	"api/internal/middleware"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// StreamGroupHandler follows one group over Server-Sent Events. Clients
+// resume with the Last-Event-ID header, or the lastEventId query parameter
+// where they can't set headers. The hub leaves out posts by users who have
+// blocked the viewer, both live and on replay.
+func StreamGroupHandler(queries *database.Queries, hub *message.Hub) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		groupID, parseErr := uuid.Parse(ctx.Query("groupId"))
+		if parseErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid groupId"})
+			gin.DefaultWriter.Write([]byte("Invalid groupId: " + parseErr.Error()))
+			return
+		}
+		checkMembership := database.CheckUserMembershipParams{
+			GroupID: groupID,
+			UserID:  user.ID,
+		}
+		isMember, checkErr := queries.CheckUserMembership(ctx.Request.Context(), checkMembership)
+		if checkErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to check membership: "+checkErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to check membership: " + checkErr.Error()))
+			return
+		}
+		if !isMember {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+
+		var lastEventID *int64
+		lastEventString := ctx.GetHeader("Last-Event-ID")
+		if lastEventString == "" {
+			lastEventString = ctx.Query("lastEventId")
+		}
+		if lastEventString != "" {
+			sequence, convErr := strconv.ParseInt(lastEventString, 10, 64)
+			if convErr != nil || sequence < 0 {
+				ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Last-Event-ID"})
+				gin.DefaultWriter.Write([]byte("Invalid Last-Event-ID: " + lastEventString))
+				return
+			}
+			lastEventID = &sequence
+		}
+
+		message.ServeSSE(hub, ctx.Writer, ctx.Request, user.ID, groupID, lastEventID)
+	}
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/producer"
+	"api/internal/middleware"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func UnreactPostHandler(queries *database.Queries, publisher *producer.Publisher) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to fetch user: "+userErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to fetch user: " + userErr.Error()))
+			return
+		}
+
+		var unreactRequest ReactionRequest
+		if bindErr := ctx.Bind(&unreactRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+
+		checkMembership := database.CheckUserMemberOfPostGroupsParams{
+			UserID: user.ID,
+			PostID: unreactRequest.PostId,
+		}
+		isMember, checkErr := queries.CheckUserMemberOfPostGroups(ctx.Request.Context(), checkMembership)
+		if checkErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to check membership: "+checkErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to check membership: " + checkErr.Error()))
+			return
+		}
+		if !isMember {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		if !requireVisiblePost(ctx, queries, unreactRequest.PostId, user.ID) {
+			return
+		}
+
+		removeReaction := database.RemoveReactionParams{
+			PostID:   unreactRequest.PostId,
+			UserID:   user.ID,
+			Reaction: strings.TrimSpace(unreactRequest.Reaction),
+		}
+		if err := queries.RemoveReaction(ctx.Request.Context(), removeReaction); err != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to remove reaction: "+err.Error())
+			gin.DefaultWriter.Write([]byte("Failed to remove reaction: " + err.Error()))
+			return
+		}
+
+		groupIDs, groupsErr := queries.ListGroupsForPost(ctx.Request.Context(), removeReaction.PostID)
+		if groupsErr != nil {
+			gin.DefaultWriter.Write([]byte("Failed to fetch post groups: " + groupsErr.Error()))
+		}
+		reaction := producer.Reaction{
+			PostID:   removeReaction.PostID,
+			UserID:   removeReaction.UserID,
+			Reaction: removeReaction.Reaction,
+		}
+		publisher.ReactionRemoved(reaction, groupIDs)
+
+		ctx.JSON(http.StatusOK, gin.H{"success": "Removed reaction"})
+	}
+}
@@ -1,19 +1,19 @@
 package handlers
 
 import (
-	"api/internal/core/aws"
 	database "api/internal/core/db"
+	"api/internal/core/imaging"
+	"api/internal/core/media"
+	"api/internal/core/storage"
 	"api/internal/middleware"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
-	"os"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
-func UploadImagePostHandler(queries *database.Queries) gin.HandlerFunc {
+func UploadImagePostHandler(queries *database.Queries, store storage.Storage) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		token, tokenErr := middleware.GetAuthToken(ctx)
 		if tokenErr != nil {
@@ -61,30 +61,26 @@ func UploadImagePostHandler(queries *database.Queries) gin.HandlerFunc {
 			return
 		}
 
-		id := uuid.New()
-		filename := fmt.Sprintf("images/%s.jpeg", id.String())
-		mediaURL := fmt.Sprintf("https://%s/%s", os.Getenv("CLOUDFRONT_DOMAIN"), filename)
+		defer file.Close()
 
-		imageParams := database.CreateImageParams{
-			ID:       id,
-			PostID:   post.ID,
-			MediaUrl: mediaURL,
+		// Processing runs before responding so the row is only created once
+		// its renditions are in storage.
+		image, storeErr := media.StoreImage(ctx.Request.Context(), queries, store, &post, file, 0)
+		if errors.Is(storeErr, imaging.ErrUnsupported) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported image format"})
+			gin.DefaultWriter.Write([]byte("Unsupported image format: " + storeErr.Error()))
+			return
 		}
-
-		image, createErr := queries.CreateImage(ctx.Request.Context(), imageParams)
-		if createErr != nil {
-			ctx.String(http.StatusInternalServerError, "Failed to create image on db")
-			gin.DefaultWriter.Write([]byte("Failed to create image" + createErr.Error()))
+		if errors.Is(storeErr, imaging.ErrTooLarge) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Image too large"})
+			gin.DefaultWriter.Write([]byte("Image too large: " + storeErr.Error()))
+			return
+		}
+		if storeErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to store image")
+			gin.DefaultWriter.Write([]byte("Failed to store image" + storeErr.Error()))
 			return
 		}
 		ctx.JSON(http.StatusOK, image)
-		go func() {
-			uploadErr := aws.ObjectUpload(filename, &file, "image/jpeg")
-			if uploadErr != nil {
-				ctx.String(http.StatusInternalServerError, "Failed to upload image S3"+uploadErr.Error())
-				gin.DefaultWriter.Write([]byte("Failed to upload to S3" + uploadErr.Error()))
-				return
-			}
-		}()
 	}
 }
@@ -30,7 +30,7 @@ func UploadLinkPostHandler(queries *database.Queries) gin.HandlerFunc {
 			return
 		}
 
-		log.Printf("Link request: ", linkRequest)
+		log.Printf("Link request: %+v", linkRequest)
 		linkParams := database.CreateLinkParams{
 			ID:       uuid.New(),
 			PostID:   linkRequest.PostId,
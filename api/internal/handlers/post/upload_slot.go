@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// ownedUploadSlot loads an upload slot of one of the user's PENDING posts.
+// When there is no such slot it writes the error response itself.
+func ownedUploadSlot(ctx *gin.Context, queries *database.Queries, userID uuid.UUID, slotID uuid.UUID) (database.UploadSlot, bool) {
+	slot, slotErr := queries.GetUploadSlot(ctx.Request.Context(), slotID)
+	if errors.Is(slotErr, pgx.ErrNoRows) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Upload not found"})
+		gin.DefaultWriter.Write([]byte("Upload not found"))
+		return slot, false
+	}
+	if slotErr != nil {
+		ctx.String(http.StatusInternalServerError, "Failed to fetch upload: "+slotErr.Error())
+		gin.DefaultWriter.Write([]byte("Failed to fetch upload: " + slotErr.Error()))
+		return slot, false
+	}
+	post, postErr := queries.GetPost(ctx.Request.Context(), slot.PostID)
+	if postErr != nil {
+		ctx.String(http.StatusInternalServerError, "Failed to fetch post: "+postErr.Error())
+		gin.DefaultWriter.Write([]byte("Failed to fetch post: " + postErr.Error()))
+		return slot, false
+	}
+	if post.UserID != userID {
+		// Don't reveal other users' uploads.
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Upload not found"})
+		gin.DefaultWriter.Write([]byte("Upload not found"))
+		return slot, false
+	}
+	if post.Status != database.PostStatusPENDING {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Post is not pending"})
+		gin.DefaultWriter.Write([]byte("Post is not pending"))
+		return slot, false
+	}
+	return slot, true
+}
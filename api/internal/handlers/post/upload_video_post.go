@@ -1,19 +1,20 @@
 package handlers
 
 import (
-	"api/internal/core/aws"
 	database "api/internal/core/db"
+	"api/internal/core/imaging"
+	"api/internal/core/media"
+	"api/internal/core/storage"
 	"api/internal/middleware"
 	"encoding/json"
-	"fmt"
+	"log"
 	"net/http"
-	"os"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
-func UploadVideoPostHandler(queries *database.Queries) gin.HandlerFunc {
+func UploadVideoPostHandler(queries *database.Queries, store storage.Storage) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		token, tokenErr := middleware.GetAuthToken(ctx)
 		if tokenErr != nil {
@@ -59,31 +60,50 @@ func UploadVideoPostHandler(queries *database.Queries) gin.HandlerFunc {
 			gin.DefaultWriter.Write([]byte("Failed to open file header as file" + fileErr.Error()))
 			return
 		}
+		defer file.Close()
 
+		// The key and content type come from the file itself, as with direct
+		// uploads, rather than from what the client says it sent.
+		contentType, sniffErr := imaging.SniffReader(file)
+		if sniffErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to read file")
+			gin.DefaultWriter.Write([]byte("Failed to read file: " + sniffErr.Error()))
+			return
+		}
 		id := uuid.New()
-		filename := fmt.Sprintf("images/%s.jpeg", id.String())
-		mediaURL := fmt.Sprintf("https://%s/%s", os.Getenv("CLOUDFRONT_DOMAIN"), filename)
+		key, ok := media.UploadKey(database.MediaTypeVIDEO, id, contentType)
+		if !ok {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported video format"})
+			gin.DefaultWriter.Write([]byte("Unsupported video format: " + contentType))
+			return
+		}
+		if fileHeader.Size <= 0 || fileHeader.Size > media.MaxUploadSize(database.MediaTypeVIDEO, contentType) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "File too large"})
+			gin.DefaultWriter.Write([]byte("File too large"))
+			return
+		}
+
+		if uploadErr := store.Put(ctx.Request.Context(), key, file, contentType); uploadErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to upload video: "+uploadErr.Error())
+			gin.DefaultWriter.Write([]byte("Failed to upload to storage: " + uploadErr.Error()))
+			return
+		}
 
 		videoParams := database.CreateVideoParams{
 			ID:       id,
 			PostID:   post.ID,
-			MediaUrl: mediaURL,
+			MediaUrl: store.URL(key),
 		}
 
 		video, createErr := queries.CreateVideo(ctx.Request.Context(), videoParams)
 		if createErr != nil {
+			if err := store.Delete(ctx.Request.Context(), []string{key}); err != nil {
+				log.Println("Failed to delete video " + key + ": " + err.Error())
+			}
 			ctx.String(http.StatusInternalServerError, "Failed to create video on db")
 			gin.DefaultWriter.Write([]byte("Failed to create video" + createErr.Error()))
 			return
 		}
 		ctx.JSON(http.StatusOK, video)
-		go func() {
-			uploadErr := aws.ObjectUpload(filename, &file, "image/jpeg")
-			if uploadErr != nil {
-				ctx.String(http.StatusInternalServerError, "Failed to upload video to S3"+uploadErr.Error())
-				gin.DefaultWriter.Write([]byte("Failed to upload to S3" + uploadErr.Error()))
-				return
-			}
-		}()
 	}
 }
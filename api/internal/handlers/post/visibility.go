@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// requireVisiblePost checks the post's author hasn't blocked the user. Such
+// posts are left out of feeds, so they are reported as not found. If the
+// check fails the response has already been written and false is returned.
+func requireVisiblePost(ctx *gin.Context, queries *database.Queries, postID uuid.UUID, userID uuid.UUID) bool {
+	checkBlocked := database.CheckPostAuthorBlockedParams{
+		PostID: postID,
+		UserID: userID,
+	}
+	isBlocked, blockedErr := queries.CheckPostAuthorBlocked(ctx.Request.Context(), checkBlocked)
+	if blockedErr != nil {
+		ctx.String(http.StatusInternalServerError, "Failed to check blocks: "+blockedErr.Error())
+		gin.DefaultWriter.Write([]byte("Failed to check blocks: " + blockedErr.Error()))
+		return false
+	}
+	if isBlocked {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+		gin.DefaultWriter.Write([]byte("Post not found: Author has blocked the user"))
+		return false
+	}
+	return true
+}
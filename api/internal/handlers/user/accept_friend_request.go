@@ -3,9 +3,11 @@ package handlers
 import (
 	database "api/internal/core/db"
 	"api/internal/middleware"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 )
 
 func AcceptFriendRequestHandler(queries *database.Queries) gin.HandlerFunc {
@@ -31,25 +33,31 @@ func AcceptFriendRequestHandler(queries *database.Queries) gin.HandlerFunc {
 			return
 		}
 
+		// The request was sent by the friend, so it is stored as (friend, user).
 		getFriendship := database.GetFriendshipParams{
-			UserID:   user.ID,
-			FriendID: friendRequest.FriendId,
+			UserID:   friendRequest.FriendId,
+			FriendID: user.ID,
 		}
 		friendship, friendshipErr := queries.GetFriendship(ctx.Request.Context(), getFriendship)
+		if errors.Is(friendshipErr, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Friend request not found"})
+			gin.DefaultWriter.Write([]byte("Friend request not found"))
+			return
+		}
 		if friendshipErr != nil {
 			ctx.String(http.StatusInternalServerError, "Error: Failed to retrieve friendship")
-			gin.DefaultWriter.Write([]byte("Failed to fetch friendship:" + userErr.Error()))
+			gin.DefaultWriter.Write([]byte("Failed to fetch friendship:" + friendshipErr.Error()))
 			return
 		}
-		if friendship.FriendID != user.ID {
-			ctx.String(http.StatusUnauthorized, "Unauthorized")
-			gin.DefaultWriter.Write([]byte("Unauthorized"))
+		if friendship.Status != database.FriendshipStatusPENDING {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Friend request not found"})
+			gin.DefaultWriter.Write([]byte("Friend request not pending"))
 			return
 		}
 
 		updateFriendship := database.UpdateFriendshipStatusParams{
-			UserID:   user.ID,
-			FriendID: friendRequest.FriendId,
+			UserID:   friendship.UserID,
+			FriendID: friendship.FriendID,
 			Status:   database.FriendshipStatusACCEPTED,
 		}
 		_, acceptErr := queries.UpdateFriendshipStatus(ctx.Request.Context(), updateFriendship)
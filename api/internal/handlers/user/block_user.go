@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/utils"
+	"api/internal/middleware"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// BlockUserHandler ends any friendship or pending request with the user and
+// blocks them. Blocked users can't be added to groups the blocker is in and
+// don't see the blocker's posts.
+func BlockUserHandler(queries *database.Queries) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+
+		var friendRequest FriendRequest
+		if bindErr := ctx.Bind(&friendRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Error: Failed to retrieve user")
+			gin.DefaultWriter.Write([]byte("Failed to fetch user:" + userErr.Error()))
+			return
+		}
+		if friendRequest.FriendId == user.ID {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Cannot block yourself"})
+			gin.DefaultWriter.Write([]byte("User tried to block themselves"))
+			return
+		}
+
+		// Drop a friendship or request stored in the other direction, but keep
+		// it if it's their own block of us.
+		getReverse := database.GetFriendshipParams{
+			UserID:   friendRequest.FriendId,
+			FriendID: user.ID,
+		}
+		reverse, reverseErr := queries.GetFriendship(ctx.Request.Context(), getReverse)
+		if reverseErr != nil && !errors.Is(reverseErr, pgx.ErrNoRows) {
+			ctx.String(http.StatusInternalServerError, "Error: Failed to retrieve friendship")
+			gin.DefaultWriter.Write([]byte("Failed to fetch friendship:" + reverseErr.Error()))
+			return
+		}
+		if reverseErr == nil && reverse.Status != database.FriendshipStatusBLOCKED {
+			deleteReverse := database.DeleteFriendshipParams{
+				UserID:   reverse.UserID,
+				FriendID: reverse.FriendID,
+			}
+			if deleteErr := queries.DeleteFriendship(ctx.Request.Context(), deleteReverse); deleteErr != nil {
+				ctx.String(http.StatusInternalServerError, "Error: Failed to delete friendship")
+				gin.DefaultWriter.Write([]byte("Failed to delete friendship:" + deleteErr.Error()))
+				return
+			}
+		}
+
+		blockUser := database.BlockUserParams{
+			UserID:      user.ID,
+			FriendID:    friendRequest.FriendId,
+			DateCreated: utils.PGTime(),
+		}
+		if _, blockErr := queries.BlockUser(ctx.Request.Context(), blockUser); blockErr != nil {
+			ctx.String(http.StatusInternalServerError, "Error: Failed to block user")
+			gin.DefaultWriter.Write([]byte("Failed to block user:" + blockErr.Error()))
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"message": "Successfully blocked user"})
+	}
+}
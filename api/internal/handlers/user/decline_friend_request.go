@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/middleware"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// DeclineFriendRequestHandler rejects a request someone sent to the user.
+func DeclineFriendRequestHandler(queries *database.Queries) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+
+		var friendRequest FriendRequest
+		if bindErr := ctx.Bind(&friendRequest); bindErr != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Request body not as specified"})
+			gin.DefaultWriter.Write([]byte("Request body not as specified: " + bindErr.Error()))
+			return
+		}
+
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Error: Failed to retrieve user")
+			gin.DefaultWriter.Write([]byte("Failed to fetch user:" + userErr.Error()))
+			return
+		}
+
+		getFriendship := database.GetFriendshipParams{
+			UserID:   friendRequest.FriendId,
+			FriendID: user.ID,
+		}
+		friendship, friendshipErr := queries.GetFriendship(ctx.Request.Context(), getFriendship)
+		if errors.Is(friendshipErr, pgx.ErrNoRows) || (friendshipErr == nil && friendship.Status != database.FriendshipStatusPENDING) {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Friend request not found"})
+			gin.DefaultWriter.Write([]byte("Friend request not found"))
+			return
+		}
+		if friendshipErr != nil {
+			ctx.String(http.StatusInternalServerError, "Error: Failed to retrieve friendship")
+			gin.DefaultWriter.Write([]byte("Failed to fetch friendship:" + friendshipErr.Error()))
+			return
+		}
+
+		deleteFriendship := database.DeleteFriendshipParams{
+			UserID:   friendship.UserID,
+			FriendID: friendship.FriendID,
+		}
+		if deleteErr := queries.DeleteFriendship(ctx.Request.Context(), deleteFriendship); deleteErr != nil {
+			ctx.String(http.StatusInternalServerError, "Error: Failed to delete friend request")
+			gin.DefaultWriter.Write([]byte("Failed to delete friend request:" + deleteErr.Error()))
+			return
+		}
+		ctx.JSON(http.StatusOK, gin.H{"message": "Successfully deleted friend request"})
+	}
+}
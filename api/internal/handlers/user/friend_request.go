@@ -3,10 +3,12 @@ package handlers
 import (
 	database "api/internal/core/db"
 	"api/internal/middleware"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 )
 
 type FriendRequest struct {
@@ -36,6 +38,47 @@ func FriendRequestHandler(queries *database.Queries) gin.HandlerFunc {
 			return
 		}
 
+		if friendRequest.FriendId == user.ID {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Cannot befriend yourself"})
+			gin.DefaultWriter.Write([]byte("User tried to befriend themselves"))
+			return
+		}
+
+		getFriendship := database.GetFriendshipBetweenParams{
+			UserID:   user.ID,
+			FriendID: friendRequest.FriendId,
+		}
+		existing, existingErr := queries.GetFriendshipBetween(ctx.Request.Context(), getFriendship)
+		if existingErr != nil && !errors.Is(existingErr, pgx.ErrNoRows) {
+			ctx.String(http.StatusInternalServerError, "Error: Failed to retrieve friendship")
+			gin.DefaultWriter.Write([]byte("Failed to fetch friendship:" + existingErr.Error()))
+			return
+		}
+		if existingErr == nil {
+			switch {
+			case existing.Status == database.FriendshipStatusBLOCKED:
+				ctx.String(http.StatusUnauthorized, "Unauthorized")
+				gin.DefaultWriter.Write([]byte("Friend request between blocked users"))
+			case existing.Status == database.FriendshipStatusPENDING && existing.UserID == friendRequest.FriendId:
+				// They already asked us, so asking back accepts their request.
+				acceptFriendship := database.UpdateFriendshipStatusParams{
+					UserID:   existing.UserID,
+					FriendID: existing.FriendID,
+					Status:   database.FriendshipStatusACCEPTED,
+				}
+				if _, acceptErr := queries.UpdateFriendshipStatus(ctx.Request.Context(), acceptFriendship); acceptErr != nil {
+					ctx.String(http.StatusInternalServerError, "Error: Failed to accept friendship")
+					gin.DefaultWriter.Write([]byte("Failed to accept friendship:" + acceptErr.Error()))
+					return
+				}
+				ctx.JSON(http.StatusOK, gin.H{"message": "Successfully updated friendship"})
+			default:
+				ctx.JSON(http.StatusConflict, gin.H{"error": "Friendship already exists"})
+				gin.DefaultWriter.Write([]byte("Friendship already exists"))
+			}
+			return
+		}
+
 		createFriendship := database.CreateFriendshipParams{
 			UserID:   user.ID,
 			FriendID: friendRequest.FriendId,
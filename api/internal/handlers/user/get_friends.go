@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/utils"
+	"api/internal/middleware"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FriendsResponse splits the user's friendships by state. Blocked only lists
+// users the caller blocked; being blocked by someone is never revealed.
+type FriendsResponse struct {
+	Friends  []database.UserProfile `json:"friends"`
+	Incoming []database.UserProfile `json:"incoming"`
+	Outgoing []database.UserProfile `json:"outgoing"`
+	Blocked  []database.UserProfile `json:"blocked"`
+}
+
+func GetFriendsHandler(queries *database.Queries) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, tokenErr := middleware.GetAuthToken(ctx)
+		if tokenErr != nil {
+			ctx.String(http.StatusUnauthorized, "Unauthorized")
+			gin.DefaultWriter.Write([]byte("Unauthorized"))
+			return
+		}
+		user, userErr := queries.GetFirebaseId(ctx.Request.Context(), token.UID)
+		if userErr != nil {
+			ctx.String(http.StatusInternalServerError, "Error: Failed to retrieve user")
+			gin.DefaultWriter.Write([]byte("Failed to fetch user:" + userErr.Error()))
+			return
+		}
+
+		friendships, listErr := queries.ListFriendshipsWithProfiles(ctx.Request.Context(), user.ID)
+		if listErr != nil {
+			ctx.String(http.StatusInternalServerError, "Error: Failed to retrieve friendships")
+			gin.DefaultWriter.Write([]byte("Failed to fetch friendships:" + listErr.Error()))
+			return
+		}
+
+		response := FriendsResponse{
+			Friends:  []database.UserProfile{},
+			Incoming: []database.UserProfile{},
+			Outgoing: []database.UserProfile{},
+			Blocked:  []database.UserProfile{},
+		}
+		for _, row := range friendships {
+			sentByUser := row.Friendship.UserID == user.ID
+			switch row.Friendship.Status {
+			case database.FriendshipStatusACCEPTED:
+				response.Friends = append(response.Friends, row.UserProfile)
+			case database.FriendshipStatusPENDING:
+				if sentByUser {
+					response.Outgoing = append(response.Outgoing, row.UserProfile)
+				} else {
+					response.Incoming = append(response.Incoming, row.UserProfile)
+				}
+			case database.FriendshipStatusBLOCKED:
+				if sentByUser {
+					response.Blocked = append(response.Blocked, row.UserProfile)
+				}
+			}
+		}
+
+		responseJSON, err := json.Marshal(response)
+		if err != nil {
+			ctx.String(http.StatusInternalServerError, "Error generating response")
+			gin.DefaultWriter.Write([]byte("Error generating response: " + err.Error()))
+			return
+		}
+
+		if handled := utils.AttachCacheHeaders(ctx, responseJSON, 0); handled {
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response)
+	}
+}
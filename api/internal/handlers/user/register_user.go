@@ -2,6 +2,8 @@ package handlers
 
 import (
 	database "api/internal/core/db"
+	"api/internal/core/invite"
+	"api/internal/core/producer"
 	"api/internal/core/utils"
 	"api/internal/middleware"
 	"net/http"
@@ -11,10 +13,11 @@ import (
 )
 
 type RegisterUserRequest struct {
-	Username string `json:"username"`
+	Username   string `json:"username"`
+	InviteCode string `json:"inviteCode"`
 }
 
-func RegisterUserHandler(queries *database.Queries) gin.HandlerFunc {
+func RegisterUserHandler(queries *database.Queries, publisher *producer.Publisher) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		token, tokenErr := middleware.GetAuthToken(ctx)
 		if tokenErr != nil {
@@ -66,23 +69,14 @@ func RegisterUserHandler(queries *database.Queries) gin.HandlerFunc {
 			return
 		}
 
-		//TEMPORARILY
-		groupId, err := uuid.Parse("b343342a-d41b-4c79-a8a8-7e0b142be6da")
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to parse groupId"})
-			return
-		}
-		addUser := database.AddUserToGroupParams{
-			GroupID:  groupId,
-			UserID:   userId,
-			Role:     database.GroupRoleMEMBER,
-			JoinedAt: utils.PGTime(),
-		}
-
-		_, addErr := queries.AddUserToGroup(ctx.Request.Context(), addUser)
-		if addErr != nil {
-			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add user to group"})
-			return
+		// New users join their first group through the invite they signed up with.
+		// The account already exists at this point, so a bad invite is reported in
+		// the logs rather than failing registration.
+		if registerRequest.InviteCode != "" {
+			_, redeemErr := invite.Redeem(ctx.Request.Context(), queries, publisher, registerRequest.InviteCode, userId)
+			if redeemErr != nil {
+				gin.DefaultWriter.Write([]byte("Failed to redeem invite on registration: " + redeemErr.Error()))
+			}
 		}
 
 		ctx.JSON(http.StatusOK, userProfile)
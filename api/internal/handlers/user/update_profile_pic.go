@@ -1,17 +1,19 @@
 package handlers
 
 import (
-	"api/internal/core/aws"
 	database "api/internal/core/db"
+	"api/internal/core/imaging"
+	"api/internal/core/storage"
 	"api/internal/middleware"
+	"bytes"
+	"errors"
 	"fmt"
 	"net/http"
-	"os"
 
 	"github.com/gin-gonic/gin"
 )
 
-func UpdateProfilePicHandler(queries *database.Queries) gin.HandlerFunc {
+func UpdateProfilePicHandler(queries *database.Queries, store storage.Storage) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		token, tokenErr := middleware.GetAuthToken(ctx)
 		if tokenErr != nil {
@@ -39,9 +41,36 @@ func UpdateProfilePicHandler(queries *database.Queries) gin.HandlerFunc {
 			gin.DefaultWriter.Write([]byte("Failed to open file header as file" + fileErr.Error()))
 			return
 		}
+		defer file.Close()
+
+		// Profile pictures go through the same processing as posts, so they
+		// are upright and carry no EXIF data.
+		processed, processErr := imaging.Process(file)
+		if errors.Is(processErr, imaging.ErrUnsupported) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported image format"})
+			gin.DefaultWriter.Write([]byte("Unsupported image format: " + processErr.Error()))
+			return
+		}
+		if errors.Is(processErr, imaging.ErrTooLarge) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Image too large"})
+			gin.DefaultWriter.Write([]byte("Image too large: " + processErr.Error()))
+			return
+		}
+		if processErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to process image")
+			gin.DefaultWriter.Write([]byte("Failed to process image: " + processErr.Error()))
+			return
+		}
+		rendition, _ := processed.Rendition(imaging.Feed, imaging.JPEG)
 
 		filename := fmt.Sprintf("profilepics/%s.jpeg", user.ID.String())
-		mediaURL := fmt.Sprintf("https://%s/%s", os.Getenv("CLOUDFRONT_DOMAIN"), filename)
+		uploadErr := store.Put(ctx.Request.Context(), filename, bytes.NewReader(rendition.Data), rendition.Format.ContentType)
+		if uploadErr != nil {
+			ctx.String(http.StatusInternalServerError, "Failed to upload profile picture")
+			gin.DefaultWriter.Write([]byte("Failed to upload to storage: " + uploadErr.Error()))
+			return
+		}
+		mediaURL := store.URL(filename)
 
 		profilepic := database.UpdateProfilePicParams{
 			UserID:     user.ID,
@@ -54,13 +83,5 @@ func UpdateProfilePicHandler(queries *database.Queries) gin.HandlerFunc {
 			return
 		}
 		ctx.JSON(http.StatusOK, gin.H{"success": "updated profile picture"})
-		go func() {
-			uploadErr := aws.ObjectUpload(filename, &file, "image/jpeg")
-			if uploadErr != nil {
-				ctx.String(http.StatusInternalServerError, "Failed to upload video to S3"+uploadErr.Error())
-				gin.DefaultWriter.Write([]byte("Failed to upload to S3" + uploadErr.Error()))
-				return
-			}
-		}()
 	}
 }
@@ -2,6 +2,8 @@ package routes
 
 import (
 	database "api/internal/core/db"
+	"api/internal/core/message"
+	"api/internal/core/producer"
 	"api/internal/handlers/group"
 	"api/internal/middleware"
 
@@ -15,10 +17,24 @@ func SetupGroupRoutes(
 	queries *database.Queries,
 	authClient *firebaseAuth.Client,
 	messagingClient *messaging.Client,
+	hub *message.Hub,
+	publisher *producer.Publisher,
 ) {
 	r := router.Group("/group", middleware.AuthMiddleware(authClient))
 	r.POST("/create-group", handlers.CreateGroupHandler(queries))
-	r.POST("/add-member", handlers.AddMemberHandler(queries))
+	r.POST("/add-member", handlers.AddMemberHandler(queries, publisher))
 	r.POST("/add-post", handlers.AddPostHandler(queries))
 	r.GET("/get-members", handlers.GetMembersHandler(queries))
+	r.GET("/get-online-members", handlers.GetOnlineMembersHandler(queries, hub))
+	r.POST("/leave-group", handlers.LeaveGroupHandler(queries, publisher))
+	r.POST("/remove-member", handlers.RemoveMemberHandler(queries, publisher))
+	r.POST("/update-member-role", handlers.UpdateMemberRoleHandler(queries))
+	r.POST("/rename-group", handlers.RenameGroupHandler(queries))
+	r.POST("/transfer-ownership", handlers.TransferOwnershipHandler(queries))
+	r.POST("/delete-group", handlers.DeleteGroupHandler(queries, publisher))
+	r.POST("/create-invite", handlers.CreateInviteHandler(queries))
+	r.GET("/preview-invite", handlers.PreviewInviteHandler(queries))
+	r.POST("/redeem-invite", handlers.RedeemInviteHandler(queries, publisher))
+	r.POST("/revoke-invite", handlers.RevokeInviteHandler(queries))
+	r.GET("/get-invites", handlers.GetInvitesHandler(queries))
 }
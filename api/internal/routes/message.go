@@ -0,0 +1,27 @@
+package routes
+
+import (
+	database "api/internal/core/db"
+	"api/internal/core/message"
+	"api/internal/core/producer"
+	"api/internal/handlers/message"
+	"api/internal/middleware"
+
+	firebaseAuth "firebase.google.com/go/v4/auth"
+	"firebase.google.com/go/v4/messaging"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupMessageRoutes(
+	router *gin.RouterGroup,
+	queries *database.Queries,
+	authClient *firebaseAuth.Client,
+	messagingClient *messaging.Client,
+	hub *message.Hub,
+	publisher *producer.Publisher,
+) {
+	r := router.Group("/message", middleware.AuthMiddleware(authClient))
+	r.POST("/send-direct-message", handlers.SendDirectMessageHandler(queries, messagingClient, hub, publisher))
+	r.GET("/get-direct-messages", handlers.GetDirectMessagesHandler(queries))
+	r.POST("/mark-direct-messages-read", handlers.MarkDirectMessagesReadHandler(queries, publisher))
+}
@@ -2,6 +2,10 @@ package routes
 
 import (
 	database "api/internal/core/db"
+	"api/internal/core/message"
+	"api/internal/core/producer"
+	"api/internal/core/score"
+	"api/internal/core/storage"
 	"api/internal/handlers/post"
 	"api/internal/middleware"
 
@@ -15,14 +19,39 @@ func SetupPostRoutes(
 	queries *database.Queries,
 	authClient *firebaseAuth.Client,
 	messagingClient *messaging.Client,
+	hub *message.Hub,
+	store storage.Storage,
+	publisher *producer.Publisher,
+	scheduler *score.Scheduler,
 ) {
 	r := router.Group("/post", middleware.AuthMiddleware(authClient))
 	r.GET("/get-group-posts", handlers.GetGroupPostsHandler(queries))
+	r.GET("/get-feed", handlers.GetFeedHandler(queries))
 	r.GET("/get-top-post", handlers.GetTopPostHandler(queries))
 	r.GET("/get-media", handlers.GetMediaHandler(queries))
-	r.POST("/create-post", handlers.CreatePostHandler(queries, messagingClient))
-	r.POST("/upload-image-post", handlers.UploadImagePostHandler(queries))
-	r.POST("/upload-video-post", handlers.UploadVideoPostHandler(queries))
+	r.POST("/create-post", handlers.CreatePostHandler(queries, messagingClient, store, publisher, scheduler))
+	r.POST("/upload-image-post", handlers.UploadImagePostHandler(queries, store))
+	r.POST("/upload-video-post", handlers.UploadVideoPostHandler(queries, store))
 	r.POST("/upload-link-post", handlers.UploadLinkPostHandler(queries))
 	r.POST("/upload-text-post", handlers.UploadTextPostHandler(queries))
+	r.POST("/create-upload-slots", handlers.CreateUploadSlotsHandler(queries, store))
+	r.POST("/create-resumable-upload", handlers.CreateResumableUploadHandler(queries, store))
+	r.POST("/presign-upload-parts", handlers.PresignUploadPartsHandler(queries, store))
+	r.GET("/get-upload-progress", handlers.GetUploadProgressHandler(queries, store))
+	r.POST("/complete-resumable-upload", handlers.CompleteResumableUploadHandler(queries, store))
+	r.POST("/complete-upload", handlers.CompleteUploadHandler(queries, store, publisher))
+	r.POST("/edit-post", handlers.EditPostHandler(queries, publisher))
+	r.POST("/remove-post-from-groups", handlers.RemovePostFromGroupsHandler(queries, publisher))
+	r.POST("/delete-post", handlers.DeletePostHandler(queries, store, publisher))
+	r.POST("/mark-viewed", handlers.MarkPostViewedHandler(queries))
+	r.POST("/react", handlers.ReactPostHandler(queries, publisher))
+	r.POST("/unreact", handlers.UnreactPostHandler(queries, publisher))
+	r.GET("/get-reactions", handlers.GetReactionsHandler(queries))
+	r.POST("/create-comment", handlers.CreateCommentHandler(queries, messagingClient, publisher))
+	r.POST("/edit-comment", handlers.EditCommentHandler(queries, publisher))
+	r.POST("/delete-comment", handlers.DeleteCommentHandler(queries, publisher))
+	r.GET("/get-comments", handlers.GetCommentsHandler(queries))
+	r.GET("/listen", handlers.PostListenerHandler(queries, hub))
+	r.GET("/stream", handlers.StreamGroupHandler(queries, hub))
+	r.GET("/event-schemas", handlers.GetEventSchemasHandler())
 }
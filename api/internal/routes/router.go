@@ -3,6 +3,9 @@ package routes
 import (
 	database "api/internal/core/db"
 	"api/internal/core/message"
+	"api/internal/core/producer"
+	"api/internal/core/score"
+	"api/internal/core/storage"
 	"api/internal/handlers"
 
 	firebaseAuth "firebase.google.com/go/v4/auth"
@@ -16,11 +19,19 @@ func SetupCoreRouter(
 	authClient *firebaseAuth.Client,
 	messagingClient *messaging.Client,
 	hub *message.Hub,
+	store storage.Storage,
+	publisher *producer.Publisher,
+	scheduler *score.Scheduler,
 ) {
 	router.GET("/", handlers.IndexHandler)
+	// The local backend has no CDN in front of it, so serve it ourselves.
+	if local, ok := store.(*storage.LocalStorage); ok {
+		router.Static(local.Path(), local.Dir())
+	}
 	r := router.Group("/v1")
-	SetupUserRoutes(r, queries, authClient, messagingClient)
-	SetupPostRoutes(r, queries, authClient, messagingClient)
-	SetupGroupRoutes(r, queries, authClient, messagingClient)
+	SetupUserRoutes(r, queries, authClient, messagingClient, store, publisher)
+	SetupPostRoutes(r, queries, authClient, messagingClient, hub, store, publisher, scheduler)
+	SetupGroupRoutes(r, queries, authClient, messagingClient, hub, publisher)
+	SetupMessageRoutes(r, queries, authClient, messagingClient, hub, publisher)
 	message.SetupKafkaConsumer(hub)
 }
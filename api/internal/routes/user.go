@@ -2,6 +2,8 @@ package routes
 
 import (
 	database "api/internal/core/db"
+	"api/internal/core/producer"
+	"api/internal/core/storage"
 	"api/internal/handlers/user"
 	"api/internal/middleware"
 
@@ -15,14 +17,22 @@ func SetupUserRoutes(
 	queries *database.Queries,
 	authClient *firebaseAuth.Client,
 	messagingClient *messaging.Client,
+	store storage.Storage,
+	publisher *producer.Publisher,
 ) {
 	r := router.Group("/user", middleware.AuthMiddleware(authClient))
 	r.GET("/get-user", handlers.GetUserHandler(queries))
 	r.GET("/get-current-user", handlers.GetCurrentUserHandler(queries))
-	r.POST("/register-user", handlers.RegisterUserHandler(queries))
+	r.POST("/register-user", handlers.RegisterUserHandler(queries, publisher))
 	r.POST("/friend-request", handlers.FriendRequestHandler(queries))
 	r.POST("/accept-friend-request", handlers.AcceptFriendRequestHandler(queries))
-	r.POST("/update-profile-pic", handlers.UpdateProfilePicHandler(queries))
+	r.POST("/decline-friend-request", handlers.DeclineFriendRequestHandler(queries))
+	r.POST("/cancel-friend-request", handlers.CancelFriendRequestHandler(queries))
+	r.POST("/unfriend", handlers.UnfriendHandler(queries))
+	r.POST("/block-user", handlers.BlockUserHandler(queries))
+	r.POST("/unblock-user", handlers.UnblockUserHandler(queries))
+	r.GET("/get-friends", handlers.GetFriendsHandler(queries))
+	r.POST("/update-profile-pic", handlers.UpdateProfilePicHandler(queries, store))
 	r.POST("/register-device-token", handlers.RegisterDeviceTokenHandler(queries))
 	r.POST("/remove-device-token", handlers.RegisterDeviceTokenHandler(queries))
 }